@@ -0,0 +1,68 @@
+package boltseq
+
+import "bytes"
+
+// subBucket is a cached sub-bucket handle.
+type subBucket struct {
+	name []byte
+	kb   KVBucket
+}
+
+// bucket returns sub-bucket with the given name, or nil if it doesn't exist.
+// Existing sub-buckets are cached, saving lookups for every operation.
+func (b *Bucket) bucket(name []byte) KVBucket {
+	for _, s := range b.subs {
+		if bytes.Equal(s.name, name) {
+			return s.kb
+		}
+	}
+
+	kb := b.loc.Bucket(name)
+	if kb != nil {
+		b.subs = append(b.subs, subBucket{name: name, kb: kb})
+	}
+	return kb
+}
+
+// createBucket returns sub-bucket with the given name, creating it if needed.
+func (b *Bucket) createBucket(name []byte) (KVBucket, error) {
+	if kb := b.bucket(name); kb != nil {
+		return kb, nil
+	}
+
+	kb, err := b.loc.CreateBucketIfNotExists(name)
+	if err != nil {
+		return nil, err
+	}
+	b.subs = append(b.subs, subBucket{name: name, kb: kb})
+	return kb, nil
+}
+
+// arenaChunkSize is the size of memory chunks allocated by arena.
+const arenaChunkSize = 4096
+
+// arena hands out buffers carved from larger chunks, to reduce number of
+// allocations when putting many small values. Buffers are never reused,
+// as bbolt requires values to stay valid for the life of the transaction.
+type arena struct {
+	chunk []byte
+}
+
+func (a *arena) alloc(n int) []byte {
+	if n > arenaChunkSize/8 {
+		return make([]byte, n)
+	}
+	if len(a.chunk) < n {
+		a.chunk = make([]byte, arenaChunkSize)
+	}
+	p := a.chunk[:n:n]
+	a.chunk = a.chunk[n:]
+	return p
+}
+
+// newValue returns value with the given header and data, allocated from the arena.
+func (a *arena) newValue(seq uint64, h *header, data []byte) Value {
+	v := Value(a.alloc(8 + h.size() + len(data)))
+	h.putValue(v, seq, data)
+	return v
+}
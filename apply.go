@@ -0,0 +1,67 @@
+package boltseq
+
+import "errors"
+
+var (
+	// ErrInvalidOp is returned by Apply for operations of unknown type or
+	// without a key or sequence number.
+	ErrInvalidOp = errors.New("invalid operation")
+
+	// ErrSeqOrder is returned by Apply if requested sequence numbers don't
+	// increase or aren't greater than those already given by the bucket.
+	ErrSeqOrder = errors.New("sequence numbers not increasing")
+)
+
+// Op is a single operation of a change stream applied by Apply.
+type Op struct {
+	Type ChangeOp
+	Key  []byte
+	Data []byte
+
+	// Seq is the sequence number to put the item with, zero meaning the
+	// next one. Deletes with nil Key delete the item with this sequence number.
+	Seq uint64
+}
+
+// Apply applies ops in order. Operations are validated up front, so an invalid
+// stream leaves the bucket untouched. Other errors, e.g. violated limits, leave
+// earlier operations applied, so the transaction should be rolled back, which
+// DB.UpdateBucket does when the error is returned.
+func (b *Bucket) Apply(ops []Op) error {
+	var last uint64
+	if bs := b.bucket(bucketNameSeq); bs != nil {
+		last = bs.Sequence()
+	}
+	for _, op := range ops {
+		switch {
+		case op.Type == ChangePut && op.Key == nil,
+			op.Type == ChangeDelete && op.Key == nil && op.Seq == 0,
+			op.Type != ChangePut && op.Type != ChangeDelete:
+			return opError("apply", op.Key, op.Seq, ErrInvalidOp)
+		case op.Type == ChangePut && op.Seq != 0:
+			if op.Seq <= last {
+				return opError("apply", op.Key, op.Seq, ErrSeqOrder)
+			}
+			last = op.Seq
+		case op.Type == ChangePut:
+			// Takes at least the next sequence number
+			last++
+		}
+	}
+
+	for _, op := range ops {
+		var err error
+		switch {
+		case op.Type == ChangePut:
+			_, err = b.put(op.Key, op.Data, op.Seq)
+		case op.Key != nil:
+			err = b.Delete(op.Key)
+		default:
+			_, err = b.DeleteSeq(op.Seq)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package boltseq
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBucket_Apply(t *testing.T) {
+	b := NewMemBucket()
+	if _, err := b.Put([]byte("x"), []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+
+	err := b.Apply([]Op{
+		{Type: ChangePut, Key: []byte("a"), Data: []byte("1"), Seq: 5},
+		{Type: ChangePut, Key: []byte("b"), Data: []byte("2")},
+		{Type: ChangePut, Key: []byte("c"), Data: []byte("3"), Seq: 10},
+		{Type: ChangeDelete, Key: []byte("x")},
+		{Type: ChangeDelete, Seq: 6},
+		{Type: ChangeDelete, Key: []byte("missing")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := orderOf(t, b); s != "a5 c10 " {
+		t.Fatal(s)
+	}
+
+	tests := []struct {
+		ops []Op
+		err error
+	}{
+		{[]Op{{Type: ChangePut, Key: []byte("d"), Seq: 10}}, ErrSeqOrder},
+		{[]Op{{Type: ChangePut, Key: []byte("d"), Seq: 12}, {Type: ChangePut, Key: []byte("e"), Seq: 11}}, ErrSeqOrder},
+		{[]Op{{Type: ChangePut, Key: []byte("d")}, {Type: ChangePut, Key: []byte("e"), Seq: 11}}, ErrSeqOrder},
+		{[]Op{{Type: ChangePut, Key: []byte("d")}, {Type: ChangePut}}, ErrInvalidOp},
+		{[]Op{{Type: ChangeDelete}}, ErrInvalidOp},
+		{[]Op{{Type: 7, Key: []byte("d")}}, ErrInvalidOp},
+	}
+	for _, tt := range tests {
+		if err := b.Apply(tt.ops); !errors.Is(err, tt.err) {
+			t.Errorf("%v: got %v, want %v", tt.ops, err, tt.err)
+		}
+	}
+
+	// Invalid streams leave the bucket untouched
+	if s := orderOf(t, b); s != "a5 c10 " {
+		t.Fatal(s)
+	}
+
+	// Requested sequence numbers follow those given meanwhile
+	err = b.Apply([]Op{
+		{Type: ChangePut, Key: []byte("f"), Data: []byte("6")},
+		{Type: ChangePut, Key: []byte("g"), Data: []byte("7"), Seq: 12},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := orderOf(t, b); s != "a5 c10 f11 g12 " {
+		t.Fatal(s)
+	}
+}
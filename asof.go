@@ -0,0 +1,162 @@
+package boltseq
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrHistoryUnavailable is returned when the state of the bucket as of a past
+// sequence number can't be resolved from the change log.
+var ErrHistoryUnavailable = errors.New("history not available")
+
+// history describes modifications made after a sequence number was the
+// maximum, as recorded in the change log.
+type history struct {
+	current bool                 // no modifications were made since
+	touched map[string]LogRecord // first record of every modified key
+}
+
+// old returns the item the touched key was as of the sequence number, or nil
+// if it didn't exist. Returns ErrHistoryUnavailable if its data wasn't logged.
+func (h *history) old(key string) (*Entry, error) {
+	r := h.touched[key]
+	switch {
+	case r.OldHash == nil:
+		return nil, nil
+	case r.OldData == nil:
+		return nil, ErrHistoryUnavailable
+	}
+	return &Entry{Seq: r.OldSeq, Key: []byte(key), Data: r.OldData}, nil
+}
+
+// history resolves modifications made after seq was the maximum sequence
+// number. The change log has to cover all of them, along with at least one
+// modification made before.
+func (b *Bucket) history(seq uint64) (*history, error) {
+	bs := b.bucket(bucketNameSeq)
+	if bs == nil || seq >= bs.Sequence() {
+		return &history{current: true}, nil
+	}
+
+	// Modifications since seq start with the first put of a greater one
+	lc := b.Log()
+	ok := lc.First()
+	for before := false; ok; ok = lc.Next() {
+		if r := lc.Record(); r.Op == ChangePut && r.Seq > seq {
+			if !before && seq > 0 {
+				return nil, ErrHistoryUnavailable
+			}
+			break
+		}
+		before = true
+	}
+	if err := lc.Err(); err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrHistoryUnavailable
+	}
+
+	h := &history{touched: make(map[string]LogRecord)}
+	for ; ok; ok = lc.Next() {
+		r := lc.Record()
+		if _, seen := h.touched[string(r.Key)]; !seen {
+			h.touched[string(r.Key)] = r
+		}
+	}
+	return h, lc.Err()
+}
+
+// GetAsOf returns Value the key had when seq was the maximum sequence number
+// in the bucket, or nil if the key didn't exist then. Keys modified since are
+// resolved from data kept in the change log with Options.LogData, holding
+// data only, without expiry, flags and origin. ErrHistoryUnavailable is
+// returned if the data wasn't logged, or the log doesn't cover modifications
+// since seq, see Options.ChangeLog.
+func (b *Bucket) GetAsOf(key []byte, seq uint64) (Value, error) {
+	if seq == 0 {
+		return nil, nil
+	}
+	h, err := b.history(seq)
+	if err != nil {
+		return nil, opError("get", key, seq, err)
+	}
+	if _, ok := h.touched[string(key)]; ok {
+		e, err := h.old(string(key))
+		if e == nil || err != nil {
+			return nil, opError("get", key, seq, err)
+		}
+		return newValue(e.Seq, e.Data), nil
+	}
+	return b.GetValue(key)
+}
+
+// ReadOnlyView gives read access to the state of a bucket as of a past
+// sequence number, see Bucket.AsOf.
+type ReadOnlyView struct {
+	b   *Bucket
+	seq uint64
+}
+
+// AsOf returns view of the bucket as it was when seq was the maximum sequence
+// number. Reads are resolved as by GetAsOf.
+func (b *Bucket) AsOf(seq uint64) *ReadOnlyView {
+	return &ReadOnlyView{b: b, seq: seq}
+}
+
+// Seq returns sequence number of the view.
+func (v *ReadOnlyView) Seq() uint64 {
+	return v.seq
+}
+
+// Get returns Value for the key. See Bucket.GetAsOf.
+func (v *ReadOnlyView) Get(key []byte) (Value, error) {
+	return v.b.GetAsOf(key, v.seq)
+}
+
+// ForEach calls fn for every item of the view in order of sequence numbers.
+// Items modified or deleted since are resolved as by GetAsOf; if any of them
+// can't be, ErrHistoryUnavailable is returned before fn is called.
+func (v *ReadOnlyView) ForEach(fn func(seq uint64, key, data []byte) error) error {
+	if v.seq == 0 {
+		return nil
+	}
+	h, err := v.b.history(v.seq)
+	if err != nil {
+		return opError("get", nil, v.seq, err)
+	}
+	var old []*Entry
+	for key := range h.touched {
+		e, err := h.old(key)
+		if err != nil {
+			return opError("get", []byte(key), v.seq, err)
+		}
+		if e != nil {
+			old = append(old, e)
+		}
+	}
+	sort.Slice(old, func(i, j int) bool { return old[i].Seq < old[j].Seq })
+
+	// Merge current items not modified since with the old ones
+	c := v.b.CursorOpts(CursorOptions{Max: v.seq})
+	err = v.b.forEach(c, c.First, c.Next, func(seq uint64, key, data []byte) error {
+		if _, ok := h.touched[string(key)]; ok {
+			return nil
+		}
+		for ; len(old) > 0 && old[0].Seq < seq; old = old[1:] {
+			if err := fn(old[0].Seq, old[0].Key, old[0].Data); err != nil {
+				return err
+			}
+		}
+		return fn(seq, key, data)
+	})
+	if err != nil {
+		return err
+	}
+	for _, e := range old {
+		if err := fn(e.Seq, e.Key, e.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
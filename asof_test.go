@@ -0,0 +1,128 @@
+package boltseq
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestBucket_asOf(t *testing.T) {
+	b := NewMemBucket()
+	b.ChangeLog = true
+
+	for _, k := range []string{"a", "b", "c"} {
+		if _, err := b.Put([]byte(k), []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Nothing changed since 3, view is the current state
+	if v, err := b.GetAsOf([]byte("b"), 3); err != nil || string(v.Data()) != "b" {
+		t.Fatal(v, err)
+	}
+	if v, err := b.GetAsOf([]byte("b"), 0); err != nil || v != nil {
+		t.Fatal(v, err)
+	}
+
+	// Key added after 2 didn't exist then, untouched keys are resolved
+	if v, err := b.GetAsOf([]byte("c"), 2); err != nil || v != nil {
+		t.Fatal(v, err)
+	}
+	if v, err := b.GetAsOf([]byte("a"), 2); err != nil || string(v.Data()) != "a" {
+		t.Fatal(v, err)
+	}
+	var got string
+	err := b.AsOf(2).ForEach(func(seq uint64, key, data []byte) error {
+		got += string(key)
+		return nil
+	})
+	if err != nil || got != "ab" {
+		t.Fatal(got, err)
+	}
+
+	// Overwritten and deleted keys can't be resolved, as data isn't logged
+	if _, err := b.Put([]byte("a"), []byte("A")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Delete([]byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"a", "b"} {
+		if _, err := b.GetAsOf([]byte(k), 3); !errors.Is(err, ErrHistoryUnavailable) {
+			t.Fatal(k, err)
+		}
+	}
+	if v, err := b.GetAsOf([]byte("c"), 3); err != nil || string(v.Data()) != "c" {
+		t.Fatal(v, err)
+	}
+	if err := b.AsOf(3).ForEach(func(uint64, []byte, []byte) error { return nil }); !errors.Is(err, ErrHistoryUnavailable) {
+		t.Fatal(err)
+	}
+	if v, err := b.AsOf(4).Get([]byte("a")); err != nil || string(v.Data()) != "A" {
+		t.Fatal(v, err)
+	}
+
+	// Modifications without the log can't be resolved
+	b2 := NewMemBucket()
+	for _, k := range []string{"a", "b"} {
+		if _, err := b2.Put([]byte(k), []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := b2.GetAsOf([]byte("a"), 1); !errors.Is(err, ErrHistoryUnavailable) {
+		t.Fatal(err)
+	}
+}
+
+func TestBucket_asOfLogData(t *testing.T) {
+	b := NewMemBucket()
+	b.ChangeLog = true
+	b.LogData = true
+
+	for _, k := range []string{"a", "b", "c", "d"} {
+		if _, err := b.Put([]byte(k), []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := b.Put([]byte("a"), []byte("A")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Delete([]byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Rename([]byte("c"), []byte("e")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Put([]byte("x"), []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+
+	for k, want := range map[string]string{"a": "a1", "b": "b2", "c": "c3", "d": "d4"} {
+		v, err := b.GetAsOf([]byte(k), 4)
+		if err != nil || fmt.Sprint(k, v.Seq()) != want || string(v.Data()) != k {
+			t.Fatal(k, v, err)
+		}
+	}
+	for _, k := range []string{"e", "x"} {
+		if v, err := b.GetAsOf([]byte(k), 4); err != nil || v != nil {
+			t.Fatal(k, v, err)
+		}
+	}
+
+	var got string
+	err := b.AsOf(4).ForEach(func(seq uint64, key, data []byte) error {
+		got += fmt.Sprint(string(key), seq, string(data), " ")
+		return nil
+	})
+	if err != nil || got != "a1a b2b c3c d4d " {
+		t.Fatal(got, err)
+	}
+	got = ""
+	err = b.AsOf(5).ForEach(func(seq uint64, key, data []byte) error {
+		got += fmt.Sprint(string(key), seq, string(data), " ")
+		return nil
+	})
+	if err != nil || got != "e3c d4d a5A " {
+		t.Fatal(got, err)
+	}
+}
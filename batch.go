@@ -0,0 +1,122 @@
+package boltseq
+
+import (
+	"bytes"
+	"sort"
+	"time"
+)
+
+// DeleteBatch deletes items with the given keys, sharing cursors over the
+// sub-buckets across deletions. Keys are processed in sorted order.
+// Returns number of items actually deleted.
+func (b *Bucket) DeleteBatch(keys [][]byte) (int, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	keys = append([][]byte{}, keys...)
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+
+	bd := b.bucket(bucketNameData)
+	if bd == nil {
+		return 0, opError("delete", keys[0], 0, ErrInvalidBucket)
+	}
+	pd := pointer{c: bd.Cursor()}
+	var ps pointer
+	if bs := b.bucket(bucketNameSeq); bs != nil {
+		ps.c = bs.Cursor()
+	}
+
+	n := 0
+	for _, key := range keys {
+		ok, err := b.deleteAt(&pd, &ps, key)
+		if err != nil {
+			return n, opError("delete", key, 0, err)
+		}
+		if ok {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// deleteAt deletes key using pointers over the data and seq sub-buckets.
+// Returns whether the key was present.
+func (b *Bucket) deleteAt(pd, ps *pointer, key []byte) (bool, error) {
+	if b.AppendOnly {
+		return false, ErrAppendOnly
+	}
+	if b.Metrics != nil {
+		defer observe(b.Metrics.ObserveDelete, time.Now(), len(key))
+	}
+
+	skey := b.storeKey(key)
+	val, ok := pd.Get(skey)
+	if !ok {
+		return false, nil
+	}
+	v := Value(val)
+	if !v.IsValid() {
+		return false, ErrInvalidValue
+	}
+	if ps.c == nil {
+		return false, ErrInvalidBucket
+	}
+	if err := b.runBeforeDelete(key); err != nil {
+		return false, err
+	}
+
+	size := entrySize(skey, v)
+	log, err := b.logDelete(key, v)
+	if err != nil {
+		return false, err
+	}
+	if err := ps.Delete(v.seqBytes()); err != nil {
+		return false, err
+	}
+	if err := b.release(v); err != nil {
+		return false, err
+	}
+	if err := pd.Delete(skey); err != nil {
+		return false, err
+	}
+	if err := b.deleteKey(skey); err != nil {
+		return false, err
+	}
+	if err := b.growSize(-size); err != nil {
+		return false, err
+	}
+	if err := b.verify([][]byte{key}, v.Seq()); err != nil {
+		return false, err
+	}
+	if err := log(); err != nil {
+		return false, err
+	}
+	return true, b.runAfterDelete(key)
+}
+
+// DeleteSeqs deletes items with the given sequence numbers using a single
+// cursor. Sequence numbers are processed in ascending order.
+// Returns number of items actually deleted.
+func (b *Bucket) DeleteSeqs(seqs []uint64) (int, error) {
+	seqs = append([]uint64{}, seqs...)
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+
+	c := b.rawCursor()
+	n := 0
+	for _, seq := range seqs {
+		if !c.Seek(seq) {
+			if err := c.Err(); err != nil {
+				return n, opError("delete", nil, seq, err)
+			}
+			break
+		}
+		if c.Seq() != seq {
+			continue
+		}
+		if err := c.Delete(); err != nil {
+			return n, opError("delete", c.Key(), seq, err)
+		}
+		n++
+	}
+	return n, nil
+}
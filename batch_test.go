@@ -0,0 +1,71 @@
+package boltseq
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func testDeleteBatch(t *testing.T, b *Bucket) {
+	big := bytes.Repeat([]byte("x"), 100)
+	for _, k := range []string{"a", "b", "c", "d", "e", "f"} {
+		if _, err := b.Put([]byte(k), big); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := b.DeleteBatch([][]byte{[]byte("e"), []byte("x"), []byte("a"), []byte("b"), []byte("a")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Fatal(n)
+	}
+	if s := orderOf(t, b); s != "c3 d4 f6 " {
+		t.Fatal(s)
+	}
+
+	n, err = b.DeleteSeqs([]uint64{6, 1, 3, 3, 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatal(n)
+	}
+	if s := orderOf(t, b); s != "d4 " {
+		t.Fatal(s)
+	}
+	if refs := blobRefs(b); len(refs) != 1 || refs[0] != 1 {
+		t.Fatal(refs)
+	}
+}
+
+func TestBucket_DeleteBatch(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		b := NewBucket(tx.Bucket(testBucketName))
+		b.Dedup = true
+		testDeleteBatch(t, b)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := NewMemBucket()
+	b.Dedup = true
+	testDeleteBatch(t, b)
+
+	b.AppendOnly = true
+	if _, err := b.DeleteBatch([][]byte{[]byte("d")}); !errors.Is(err, ErrAppendOnly) {
+		t.Fatal(err)
+	}
+}
@@ -4,8 +4,10 @@
 package boltseq
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
+	"time"
 
 	bolt "go.etcd.io/bbolt"
 )
@@ -14,6 +16,7 @@ import (
 var (
 	bucketNameData = []byte("data")
 	bucketNameSeq  = []byte("seq")
+	bucketNameMeta = []byte("meta")
 )
 
 // Location is implemented by bolt.Tx and bolt.Bucket
@@ -28,8 +31,16 @@ func DataBucket(b Location) *bolt.Bucket {
 	return b.Bucket(bucketNameData)
 }
 
+// SeqBucket returns bucket mapping sequence numbers to keys, or nil if it
+// doesn't exist. Its keys are sequence numbers encoded as 8-byte big-endian
+// integers, so they sort in sequence order; values are the keys of items.
+// The format is part of the package's compatibility guarantee.
+func SeqBucket(b Location) *bolt.Bucket {
+	return b.Bucket(bucketNameSeq)
+}
+
 // Value is a value for a key stored in the bucket.
-// It consists of sequence number and data.
+// It consists of sequence number, optional header and data.
 type Value []byte
 
 func newValue(seq uint64, val []byte) Value {
@@ -41,111 +52,414 @@ func newValue(seq uint64, val []byte) Value {
 
 // IsValid tells whether value is valid.
 func (v Value) IsValid() bool {
-	return len(v) >= 8
+	_, _, ok := parseHeader(v)
+	return ok
 }
 
-// Data returns data part of the value.
+// Data returns data part of the value, or nil if value is invalid.
 func (v Value) Data() []byte {
-	return v[8:]
+	d, _ := v.DataOK()
+	return d
+}
+
+// DataOK returns data part of the value and whether the value is valid.
+func (v Value) DataOK() ([]byte, bool) {
+	_, n, ok := parseHeader(v)
+	if !ok {
+		return nil, false
+	}
+	return v[n:], true
 }
 
-// Seq returns seequence number of the value.
+// Seq returns seequence number of the value, or 0 if value is invalid.
 func (v Value) Seq() uint64 {
-	return binary.BigEndian.Uint64(v[:8])
+	seq, _ := v.SeqOK()
+	return seq
+}
+
+// SeqOK returns sequence number of the value and whether the value is valid.
+func (v Value) SeqOK() (uint64, bool) {
+	if !v.IsValid() {
+		return 0, false
+	}
+	return binary.BigEndian.Uint64(v[:8]) &^ seqExtBit, true
+}
+
+// DataCopy returns copy of the data part of the value, which stays valid
+// after the transaction ends. Returns nil if value is invalid.
+func (v Value) DataCopy() []byte {
+	d, ok := v.DataOK()
+	if !ok {
+		return nil
+	}
+	return append([]byte{}, d...)
+}
+
+// Clone returns copy of the value.
+func (v Value) Clone() Value {
+	if v == nil {
+		return nil
+	}
+	return append(Value{}, v...)
 }
 
 func (v Value) seqBytes() []byte {
-	return v[:8]
+	if v[0]&0x80 == 0 {
+		return v[:8]
+	}
+	return seqKey(v.Seq())
+}
+
+// Options holds optional bucket settings. The zero value is ready to use.
+type Options struct {
+	// Metrics, if set, receives observations of bucket operations.
+	Metrics Metrics
+
+	// Tracer, if set, is used by context-accepting operations to start spans.
+	Tracer Tracer
+
+	// CancelCheckInterval is the number of items after which context-accepting
+	// iterations check for cancellation. Defaults to DefaultCancelCheckInterval.
+	CancelCheckInterval int
+
+	// OnCorrupt, if set, is called for corrupted items found during iteration,
+	// which are then skipped instead of stopping it. Iteration stops if OnCorrupt
+	// returns an error.
+	OnCorrupt func(err *CorruptionError) error
+
+	// SeqGenerator, if set, provides sequence numbers for new items instead
+	// of the bucket's own counter.
+	SeqGenerator SeqGenerator
+
+	// Dedup enables storing identical data only once. Data is kept in a blob
+	// sub-bucket under its SHA-256 hash and reference counted, so blobs are
+	// deleted with the last item referencing them. Quota counts references only.
+	Dedup bool
+
+	// Compression tells whether data is compressed. Compressed data is
+	// decompressed transparently on reading.
+	Compression Compression
+
+	// ChunkSize, if positive, is the size above which data is split into
+	// chunks stored as separate entries, as bbolt handles large values poorly.
+	// DefaultChunkSize is used if negative.
+	ChunkSize int
+
+	// AppendOnly makes the bucket reject overwrites, deletes and reordering
+	// of existing items with ErrAppendOnly.
+	AppendOnly bool
+
+	// Overwrite tells what Put does when the key already exists.
+	Overwrite OverwritePolicy
+
+	// Paranoid makes modifications verify that touched items are consistent
+	// across sub-buckets afterwards, failing with CorruptionError otherwise.
+	// It's meant for catching bugs in testing and staging.
+	Paranoid bool
+
+	// ChangeLog enables recording every modification, with hashes of data
+	// before and after it, in the log sub-bucket, see Log. Records older
+	// than LogRetention, if positive, are pruned on writes.
+	ChangeLog    bool
+	LogRetention time.Duration
+
+	// LogData makes the change log also keep data items had before they
+	// were modified or deleted, so GetAsOf and AsOf can resolve them. The log
+	// grows by the size of replaced data.
+	LogData bool
+
+	// Now, if set, returns current time used for expiry of items.
+	// Defaults to time.Now.
+	Now func() time.Time
+
+	// Detach makes Get and cursors return copies of keys and data, which stay
+	// valid after the transaction ends, instead of slices owned by the database.
+	Detach bool
+
+	// HashKeys, if positive, makes keys longer than HashKeys bytes, but at
+	// least 33, stored under their SHA-256 hash, with original keys kept in
+	// the keys sub-bucket. Keys are taken and returned in original form, but
+	// hashed keys sort by hash in key order, so PrefixAfter checks them all.
+	HashKeys int
+
+	// TokenKey is used to encrypt and authenticate continuation tokens
+	// returned by Cursor.ResumeToken, which fails without it.
+	TokenKey []byte
+
+	// Limits are enforced by Put before anything is written.
+	Limits Limits
+
+	// Quota limits total stored bytes of keys and values, including headers.
+	// Put fails with ErrQuotaExceeded if it would exceed the quota, unless
+	// QuotaEvict is set, in which case the oldest items are deleted to make room.
+	Quota      int64
+	QuotaEvict bool
+
+	hooks hooks
+	enc   *encryption
 }
 
 // Bucket reporesents boltseq.Bucket at given location.
 type Bucket struct {
-	loc Location
+	loc Store
+	Options
+
+	subs  []subBucket
+	arena arena
+	bulk  bool // data keys are added in order
+
+	ver   Version // cached format version, if verOK
+	verOK bool
 }
 
 // NewBucket creates a boltseq bucket at given location.
 // This call has no side-effects, in particular sub-buckets are created on Put.
 func NewBucket(loc Location) *Bucket {
-	return &Bucket{loc: loc}
+	return NewStoreBucket(BoltStore(loc))
+}
+
+// NewStoreBucket creates a boltseq bucket on top of a custom storage backend.
+func NewStoreBucket(s Store) *Bucket {
+	return &Bucket{loc: s}
 }
 
 var (
 	ErrInvalidValue  = errors.New("invalid value")
 	ErrInvalidBucket = errors.New("invalid bucket")
 	ErrInvalidKey    = errors.New("invalid key")
+	ErrSeqExists     = errors.New("sequence number already used")
+	ErrInvalidSeq    = errors.New("invalid sequence number")
 )
 
 // Put adds key-value pair into the bucket. Returns sequence number and error, if any.
 // The key is always given a new sequence number, even if it already exists.
 func (b *Bucket) Put(key []byte, value []byte) (uint64, error) {
-	bd, err := b.loc.CreateBucketIfNotExists(bucketNameData)
+	return b.put(key, value, 0)
+}
+
+// put stores key-value pair with sequence number seq, or the next sequence
+// number if seq is zero.
+func (b *Bucket) put(key []byte, value []byte, seq uint64) (uint64, error) {
+	return b.putHeader(key, value, seq, header{})
+}
+
+// putHeader is like put, additionally storing fields of header h.
+func (b *Bucket) putHeader(key []byte, value []byte, seq uint64, h header) (_ uint64, err error) {
+	if b.Metrics != nil {
+		defer observe(b.Metrics.ObservePut, time.Now(), len(key)+len(value))
+	}
+	defer func() { err = opError("put", key, seq, err) }()
+
+	value, err = b.runBeforePut(key, value)
 	if err != nil {
 		return 0, err
 	}
 
-	bs, err := b.loc.CreateBucketIfNotExists(bucketNameSeq)
+	if err := b.Limits.check(key, value); err != nil {
+		return 0, err
+	}
+	if err := b.checkKey(key); err != nil {
+		return 0, err
+	}
+	// Expired items count as absent, their value is released when overwritten
+	if old := b.getLive(key); old != nil {
+		switch {
+		case b.AppendOnly:
+			return 0, ErrAppendOnly
+		case b.Overwrite == OverwriteReject:
+			return 0, ErrKeyExists
+		case b.Overwrite == OverwriteKeepSeq && seq == 0 && old.IsValid():
+			seq = old.Seq()
+		}
+	}
+
+	bd, bs, err := b.createDataBuckets()
 	if err != nil {
 		return 0, err
 	}
+	if h.flags&flagExpiry != 0 {
+		if err := b.markTTL(); err != nil {
+			return 0, err
+		}
+	}
+	skey := b.storeKey(key)
 
-	// Delete current value
-	if v := Value(bd.Get(key)); v != nil {
-		if !v.IsValid() {
-			return 0, ErrInvalidValue
+	// Requested sequence must be valid and free, unless taken by the key itself
+	if seq >= seqExtBit {
+		return 0, ErrInvalidSeq
+	}
+	if seq != 0 {
+		if k := bs.Get(seqKey(seq)); k != nil && !bytes.Equal(k, skey) {
+			return 0, ErrSeqExists
 		}
-		if err := bs.Delete(v.seqBytes()); err != nil {
+	}
+
+	// Get next sequence, or make sure the requested one won't be given again
+	if seq == 0 {
+		if seq, err = b.nextSeq(bs); err != nil {
 			return 0, err
 		}
-		if err := bd.Delete(key); err != nil {
+	} else if seq > bs.Sequence() {
+		if err := bs.SetSequence(seq); err != nil {
 			return 0, err
 		}
 	}
 
-	// Get next sequence
-	seq, err := bs.NextSequence()
+	// Encode data, e.g. deduplicate
+	enc, err := b.encode(skey, seq, value, h)
 	if err != nil {
-		return seq, err
+		return 0, err
+	}
+
+	// Make room for the new value
+	size := int64(len(skey)) + enc.size()
+	oldSize, err := b.reserve(key, size)
+	if err != nil {
+		return 0, err
+	}
+	if err := enc.commit(); err != nil {
+		return 0, err
+	}
+
+	// Delete current seq->key mapping. Data entry is overwritten below.
+	var oldSeq uint64
+	var old *logState
+	if v := Value(bd.Get(skey)); v != nil {
+		oldSeq = v.Seq()
+		if b.ChangeLog {
+			if old, err = b.logState(key, v); err != nil {
+				return 0, err
+			}
+		}
+		if err := bs.Delete(v.seqBytes()); err != nil {
+			return 0, err
+		}
+		if err := b.release(v); err != nil {
+			return 0, err
+		}
 	}
 
 	// Make value
-	val := newValue(seq, value)
+	val := b.arena.newValue(seq, &enc.h, enc.data)
 
 	// Add seq->key mapping. Fill percent is set to 100% as
 	// we add keys in order.
-	bs.FillPercent = 1
-	if err := bs.Put(val.seqBytes(), key); err != nil {
+	setFillPercent(bs, 1)
+	if err := bs.Put(val.seqBytes(), skey); err != nil {
+		return seq, err
+	}
+
+	if b.bulk {
+		setFillPercent(bd, 1)
+	}
+
+	if err := bd.Put(skey, val); err != nil {
+		return seq, err
+	}
+	if err := b.putKey(skey, key); err != nil {
+		return seq, err
+	}
+
+	if err := b.growSize(size - oldSize); err != nil {
 		return seq, err
 	}
+	if err := b.verify([][]byte{skey}, oldSeq, seq); err != nil {
+		return seq, err
+	}
+	if b.ChangeLog {
+		if err := b.logChange(ChangePut, seq, key, old, dataHash(value), h.origin); err != nil {
+			return seq, err
+		}
+	}
 
-	return seq, bd.Put(key, val)
+	return seq, b.runAfterPut(seq, key, value)
 }
 
-// Get returns Value for the key
+// Get returns Value for the key. If the value can't be decoded, e.g. its
+// deduplicated data is missing, an invalid non-nil value is returned;
+// use GetValue to learn the reason.
 func (b *Bucket) Get(key []byte) Value {
-	bd := b.loc.Bucket(bucketNameData)
+	v, err := b.GetValue(key)
+	if err != nil {
+		return Value{}
+	}
+	return v
+}
+
+// GetValue returns Value for the key, or nil if the key doesn't exist.
+// Stored data is decoded, so returned Value holds data as passed to Put.
+func (b *Bucket) GetValue(key []byte) (v Value, err error) {
+	if b.Metrics != nil {
+		defer func(start time.Time) { b.Metrics.ObserveGet(time.Since(start), len(v)) }(time.Now())
+	}
+
+	v = b.getLive(key)
+	if v == nil {
+		return nil, nil
+	}
+	if v, err = b.decode(b.storeKey(key), v); err != nil {
+		return nil, opError("get", key, 0, err)
+	}
+	if b.Detach {
+		v = v.Clone()
+	}
+	return v, nil
+}
+
+func (b *Bucket) get(key []byte) Value {
+	bd := b.bucket(bucketNameData)
 	if bd == nil {
 		return nil
 	}
-	return Value(bd.Get(key))
+	return Value(bd.Get(b.storeKey(key)))
+}
+
+// getLive is like get, but returns nil for expired items.
+func (b *Bucket) getLive(key []byte) Value {
+	v := b.get(key)
+	if v != nil && b.expired(v) {
+		return nil
+	}
+	return v
 }
 
 // GetSeq returns data value for a key with sequence number `seq`
-func (b *Bucket) GetSeq(seq uint64) []byte {
-	bs := b.loc.Bucket(bucketNameSeq)
+func (b *Bucket) GetSeq(seq uint64) (key []byte) {
+	if b.Metrics != nil {
+		defer func(start time.Time) { b.Metrics.ObserveGet(time.Since(start), len(key)) }(time.Now())
+	}
+
+	bs := b.bucket(bucketNameSeq)
 	if bs == nil {
 		return nil
 	}
-	return bs.Get(newValue(seq, nil).seqBytes())
+	key = bs.Get(seqKey(seq))
+	if key != nil && b.hasTTL() && b.expired(b.get(key)) {
+		return nil
+	}
+	return b.userKey(key)
 }
 
 // Delete deletes a key
 func (b *Bucket) Delete(key []byte) error {
-	bd := b.loc.Bucket(bucketNameData)
+	if b.Metrics != nil {
+		defer observe(b.Metrics.ObserveDelete, time.Now(), len(key))
+	}
+	return opError("delete", key, 0, b.delete(key))
+}
+
+func (b *Bucket) delete(key []byte) error {
+	if b.AppendOnly {
+		return ErrAppendOnly
+	}
+
+	bd := b.bucket(bucketNameData)
 	if bd == nil {
 		return ErrInvalidBucket
 	}
 
-	v := Value(bd.Get(key))
+	skey := b.storeKey(key)
+	v := Value(bd.Get(skey))
 	if v == nil {
 		return nil
 	}
@@ -153,46 +467,120 @@ func (b *Bucket) Delete(key []byte) error {
 		return ErrInvalidValue
 	}
 
-	bs := b.loc.Bucket(bucketNameSeq)
+	bs := b.bucket(bucketNameSeq)
 	if bs == nil {
 		return ErrInvalidBucket
 	}
 
+	if err := b.runBeforeDelete(key); err != nil {
+		return err
+	}
+	log, err := b.logDelete(key, v)
+	if err != nil {
+		return err
+	}
+
 	if err := bs.Delete(v.seqBytes()); err != nil {
 		return err
 	}
 
-	return bd.Delete(key)
+	if err := b.release(v); err != nil {
+		return err
+	}
+
+	if err := bd.Delete(skey); err != nil {
+		return err
+	}
+	if err := b.deleteKey(skey); err != nil {
+		return err
+	}
+
+	if err := b.growSize(-entrySize(skey, v)); err != nil {
+		return err
+	}
+	if err := b.verify([][]byte{skey}, v.Seq()); err != nil {
+		return err
+	}
+	if err := log(); err != nil {
+		return err
+	}
+
+	return b.runAfterDelete(key)
 }
 
-// DeleteSeq deletes a key with sequence number `seq`
-func (b *Bucket) DeleteSeq(seq uint64) error {
-	c := b.Cursor()
+// DeleteSeq deletes a key with sequence number `seq`.
+// Returns whether the sequence number was present.
+func (b *Bucket) DeleteSeq(seq uint64) (deleted bool, err error) {
+	c := b.rawCursor()
 	if !c.Seek(seq) {
-		return c.Err()
+		return false, opError("delete", nil, seq, c.Err())
 	}
 	if c.Seq() != seq {
-		return nil
+		return false, nil
 	}
 
-	return c.Delete()
+	if err := c.Delete(); err != nil {
+		return false, opError("delete", c.Key(), seq, err)
+	}
+	return true, nil
+}
+
+// ForEach calls fn for every item in the bucket in order of sequence numbers.
+// Iteration stops on the first error returned by fn.
+func (b *Bucket) ForEach(fn func(seq uint64, key, data []byte) error) error {
+	c := b.Cursor()
+	return b.forEach(c, c.First, c.Next, fn)
+}
+
+// forEach calls fn for every item visited by moving cursor c with first,
+// then next, until either returns false.
+func (b *Bucket) forEach(c *Cursor, first, next func() bool, fn func(seq uint64, key, data []byte) error) error {
+	for ok := first(); ok; ok = next() {
+		data, err := c.Data()
+		if err == nil && b.OnCorrupt != nil {
+			err = c.verify()
+		}
+		if err != nil {
+			if b.OnCorrupt == nil {
+				return err
+			}
+			if err := b.OnCorrupt(&CorruptionError{Seq: c.Seq(), Key: c.Key(), Err: err}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := fn(c.Seq(), c.Key(), data); err != nil {
+			return err
+		}
+	}
+	return c.Err()
+}
+
+// rawCursor returns iterator visiting expired items too, for operations
+// that need to see every sequence number in use.
+func (b *Bucket) rawCursor() *Cursor {
+	c := b.Cursor()
+	c.ttl = false
+	return c
 }
 
 // Cursor returns iterator over the bucket
 func (b *Bucket) Cursor() *Cursor {
-	var cs, cd *bolt.Cursor
+	var cs, cd KVCursor
 
-	bs := b.loc.Bucket(bucketNameSeq)
+	bs := b.bucket(bucketNameSeq)
 	if bs != nil {
 		cs = bs.Cursor()
 	}
-	bd := b.loc.Bucket(bucketNameData)
+	bd := b.bucket(bucketNameData)
 	if bd != nil {
 		cd = bd.Cursor()
 	}
 
 	return &Cursor{
-		cs: cs,
-		dp: pointer{c: cd},
+		cs:  cs,
+		dp:  pointer{c: cd},
+		b:   b,
+		ttl: cs != nil && b.hasTTL(),
 	}
 }
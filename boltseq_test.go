@@ -2,6 +2,7 @@ package boltseq
 
 import (
 	"crypto/sha1"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -163,3 +164,171 @@ func TestBucket_putMany(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestValue_invalid(t *testing.T) {
+	for _, v := range []Value{nil, Value("short")} {
+		if v.IsValid() {
+			t.Fatal(v)
+		}
+		if d, ok := v.DataOK(); ok || d != nil || v.Data() != nil {
+			t.Fatal(v, d)
+		}
+		if seq, ok := v.SeqOK(); ok || seq != 0 || v.Seq() != 0 {
+			t.Fatal(v, seq)
+		}
+	}
+
+	v := newValue(7, []byte("x"))
+	if d, ok := v.DataOK(); !ok || string(d) != "x" {
+		t.Fatal(d)
+	}
+	if seq, ok := v.SeqOK(); !ok || seq != 7 {
+		t.Fatal(seq)
+	}
+}
+
+func TestBucket_corruptValue(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		b := NewBucket(tx.Bucket(testBucketName))
+		if _, err := b.Put([]byte("x"), []byte("v")); err != nil {
+			t.Fatal(err)
+		}
+		if err := DataBucket(tx.Bucket(testBucketName)).Put([]byte("x"), []byte("bad")); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := b.Put([]byte("x"), []byte("v")); !errors.Is(err, ErrInvalidValue) {
+			t.Fatal(err)
+		}
+		if err := b.Delete([]byte("x")); !errors.Is(err, ErrInvalidValue) {
+			t.Fatal(err)
+		}
+		c := b.Cursor()
+		if !c.First() {
+			t.Fatal(c.Err())
+		}
+		if _, err := c.Data(); !errors.Is(err, ErrInvalidValue) {
+			t.Fatal(err)
+		}
+		if err := b.ForEach(func(uint64, []byte, []byte) error { return nil }); !errors.Is(err, ErrInvalidValue) {
+			t.Fatal(err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkBucket_put(b *testing.B) {
+	db, err := newTestDB()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	keys := make([][]byte, b.N)
+	for n := range keys {
+		keys[n] = []byte(fmt.Sprintf("%08d", n))
+	}
+	data := []byte("data")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	err = db.Update(func(tx *bolt.Tx) error {
+		bb := NewBucket(tx.Bucket(testBucketName))
+		for _, k := range keys {
+			if _, err := bb.Put(k, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+func TestBucket_overwrite(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	keys := []string{"a", "b", "c"}
+	for round := 0; round < 100; round++ {
+		err = db.Update(func(tx *bolt.Tx) error {
+			b := NewBucket(tx.Bucket(testBucketName))
+			for _, k := range keys {
+				if _, err := b.Put([]byte(k), []byte(fmt.Sprint(round))); err != nil {
+					t.Fatal(err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		tb := tx.Bucket(testBucketName)
+		b := NewBucket(tb)
+
+		for n, k := range keys {
+			seq := uint64(99*len(keys) + n + 1)
+			if v := b.Get([]byte(k)); v.Seq() != seq || string(v.Data()) != "99" {
+				t.Fatal(k, v.Seq(), string(v.Data()))
+			}
+			if key := b.GetSeq(seq); string(key) != k {
+				t.Fatal(seq, key)
+			}
+		}
+
+		// Stale entries must be gone from both sub-buckets and not pile up
+		ds, ss := DataBucket(tb).Stats(), tb.Bucket(bucketNameSeq).Stats()
+		if ds.KeyN != len(keys) || ss.KeyN != len(keys) {
+			t.Fatal(ds.KeyN, ss.KeyN)
+		}
+		if ds.LeafPageN > 1 || ss.LeafPageN > 1 {
+			t.Fatal(ds.LeafPageN, ss.LeafPageN)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSeqBucket(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		tb := tx.Bucket(testBucketName)
+		if SeqBucket(tb) != nil {
+			t.Fatal("seq bucket exists")
+		}
+		if _, err := NewBucket(tb).Put([]byte("x"), nil); err != nil {
+			t.Fatal(err)
+		}
+		k, v := SeqBucket(tb).Cursor().First()
+		if string(k) != "\x00\x00\x00\x00\x00\x00\x00\x01" || string(v) != "x" {
+			t.Fatal(k, v)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,109 @@
+// boltseqtest provides helpers for testing code using boltseq buckets.
+package boltseqtest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tg/boltseq"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Entry describes a bucket item. Zero Seq matches any sequence number.
+type Entry struct {
+	Seq  uint64
+	Key  string
+	Data string
+}
+
+func (e Entry) String() string {
+	return fmt.Sprintf("%d %q=%q", e.Seq, e.Key, e.Data)
+}
+
+// OpenDB opens bolt database in a temporary file. The returned function closes
+// the database and removes the file.
+func OpenDB(tb testing.TB) (*bolt.DB, func()) {
+	tb.Helper()
+
+	f, err := ioutil.TempFile("", "boltseqtest")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	f.Close()
+
+	db, err := bolt.Open(f.Name(), 0600, nil)
+	if err != nil {
+		os.Remove(f.Name())
+		tb.Fatal(err)
+	}
+
+	return db, func() {
+		db.Close()
+		os.Remove(f.Name())
+	}
+}
+
+// Seed puts entries into the bucket in order. Entries with non-zero Seq must
+// receive exactly that sequence number.
+func Seed(tb testing.TB, b *boltseq.Bucket, entries ...Entry) {
+	tb.Helper()
+
+	for _, e := range entries {
+		seq, err := b.Put([]byte(e.Key), []byte(e.Data))
+		if err != nil {
+			tb.Fatalf("seed %v: %v", e, err)
+		}
+		if e.Seq != 0 && seq != e.Seq {
+			tb.Fatalf("seed %v: got seq %d", e, seq)
+		}
+	}
+}
+
+// Entries returns all items of the bucket in sequence order.
+func Entries(tb testing.TB, b *boltseq.Bucket) []Entry {
+	tb.Helper()
+
+	var got []Entry
+	err := b.ForEach(func(seq uint64, key, data []byte) error {
+		got = append(got, Entry{Seq: seq, Key: string(key), Data: string(data)})
+		return nil
+	})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return got
+}
+
+// AssertEntries checks the bucket holds exactly the given entries in order.
+// On mismatch, the test fails with a line-by-line diff.
+func AssertEntries(tb testing.TB, b *boltseq.Bucket, want ...Entry) {
+	tb.Helper()
+
+	if diff := Diff(Entries(tb, b), want); diff != "" {
+		tb.Fatalf("bucket contents mismatch (-got +want):\n%s", diff)
+	}
+}
+
+// Diff returns readable difference between got and want entries, or empty
+// string if they match. Zero Seq in want matches any sequence number.
+func Diff(got, want []Entry) string {
+	var sb strings.Builder
+	for n := 0; n < len(got) || n < len(want); n++ {
+		switch {
+		case n >= len(want):
+			fmt.Fprintf(&sb, "- [%d] %v\n", n, got[n])
+		case n >= len(got):
+			fmt.Fprintf(&sb, "+ [%d] %v\n", n, want[n])
+		case !match(got[n], want[n]):
+			fmt.Fprintf(&sb, "- [%d] %v\n+ [%d] %v\n", n, got[n], n, want[n])
+		}
+	}
+	return sb.String()
+}
+
+func match(got, want Entry) bool {
+	return (want.Seq == 0 || got.Seq == want.Seq) && got.Key == want.Key && got.Data == want.Data
+}
@@ -0,0 +1,33 @@
+package boltseqtest
+
+import (
+	"testing"
+
+	"github.com/tg/boltseq"
+)
+
+func TestSeedAssert(t *testing.T) {
+	db, cleanup := OpenDB(t)
+	defer cleanup()
+
+	err := boltseq.NewDB(db).UpdateBucket([][]byte{[]byte("b")}, func(b *boltseq.Bucket) error {
+		Seed(t, b, Entry{Key: "a", Data: "1"}, Entry{Key: "b", Data: "2"}, Entry{Key: "a", Data: "3"})
+		AssertEntries(t, b, Entry{Seq: 2, Key: "b", Data: "2"}, Entry{Key: "a", Data: "3"})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	got := []Entry{{1, "a", "1"}, {2, "b", "2"}}
+	if d := Diff(got, []Entry{{0, "a", "1"}, {2, "b", "2"}}); d != "" {
+		t.Fatal(d)
+	}
+
+	exp := "- [1] 2 \"b\"=\"2\"\n+ [1] 2 \"b\"=\"x\"\n+ [2] 0 \"c\"=\"\"\n"
+	if d := Diff(got, []Entry{{1, "a", "1"}, {2, "b", "x"}, {0, "c", ""}}); d != exp {
+		t.Fatal(d)
+	}
+}
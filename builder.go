@@ -0,0 +1,132 @@
+package boltseq
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrNotEmpty is returned by Builder when the target bucket already holds items
+// or has given sequence numbers.
+var ErrNotEmpty = errors.New("bucket not empty")
+
+// Builder declares full contents of a bucket, e.g. for test fixtures, and
+// writes them with Build. Declarations are validated as they're made and the
+// first error is returned by Build, so calls can be chained.
+type Builder struct {
+	entries []Entry
+	tombs   []uint64
+	meta    map[string][]byte
+
+	seqs map[uint64]bool
+	keys map[string]bool
+	err  error
+}
+
+// NewBuilder returns an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{
+		meta: make(map[string][]byte),
+		seqs: make(map[uint64]bool),
+		keys: make(map[string]bool),
+	}
+}
+
+// addSeq validates seq isn't used yet.
+func (bl *Builder) addSeq(seq uint64) bool {
+	switch {
+	case bl.err != nil:
+		return false
+	case seq == 0 || seq >= seqExtBit:
+		bl.err = opError("build", nil, seq, ErrInvalidSeq)
+	case bl.seqs[seq]:
+		bl.err = opError("build", nil, seq, ErrSeqExists)
+	default:
+		bl.seqs[seq] = true
+		return true
+	}
+	return false
+}
+
+// Put declares item with the given sequence number, key and data.
+func (bl *Builder) Put(seq uint64, key, data []byte) *Builder {
+	switch {
+	case bl.err != nil:
+	case key == nil:
+		bl.err = opError("build", key, seq, ErrInvalidKey)
+	case bl.keys[string(key)]:
+		bl.err = opError("build", key, seq, ErrKeyExists)
+	case bl.addSeq(seq):
+		bl.keys[string(key)] = true
+		bl.entries = append(bl.entries, Entry{Seq: seq, Key: key, Data: data}.Clone())
+	}
+	return bl
+}
+
+// Tombstone declares seq as given to an item that no longer exists, so the
+// bucket never gives it again, like after Delete.
+func (bl *Builder) Tombstone(seq uint64) *Builder {
+	if bl.addSeq(seq) {
+		bl.tombs = append(bl.tombs, seq)
+	}
+	return bl
+}
+
+// Meta declares metadata value stored under key, see Bucket.Meta.
+func (bl *Builder) Meta(key string, value []byte) *Builder {
+	if bl.err == nil {
+		bl.meta[key] = append([]byte{}, value...)
+	}
+	return bl
+}
+
+// Build writes declared contents into b, which must be empty. Items are put
+// in sequence order, so bucket options, e.g. compression, apply to them.
+// Use DB.UpdateBucket, or BuildDB, to write everything in one transaction.
+func (bl *Builder) Build(b *Bucket) error {
+	if bl.err != nil {
+		return bl.err
+	}
+	if bs := b.bucket(bucketNameSeq); bs != nil && bs.Sequence() > 0 {
+		return opError("build", nil, bs.Sequence(), ErrNotEmpty)
+	}
+
+	entries := append([]Entry{}, bl.entries...)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Seq < entries[j].Seq })
+	for _, e := range entries {
+		if _, err := b.put(e.Key, e.Data, e.Seq); err != nil {
+			return err
+		}
+	}
+
+	var last uint64
+	for _, seq := range bl.tombs {
+		if seq > last {
+			last = seq
+		}
+	}
+	if last > 0 {
+		_, bs, err := b.createDataBuckets()
+		if err != nil {
+			return err
+		}
+		if last > bs.Sequence() {
+			if err := bs.SetSequence(last); err != nil {
+				return err
+			}
+		}
+	}
+
+	m := b.Meta()
+	for key, value := range bl.meta {
+		if err := m.Set(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BuildDB writes declared contents into bucket at path within a single
+// read-write transaction, see Build.
+func (bl *Builder) BuildDB(db *DB, path [][]byte) error {
+	return db.UpdateBucket(path, bl.Build)
+}
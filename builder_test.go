@@ -0,0 +1,82 @@
+package boltseq
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	b := NewMemBucket()
+	err := NewBuilder().
+		Put(5, []byte("b"), []byte("2")).
+		Put(2, []byte("a"), []byte("1")).
+		Tombstone(3).
+		Tombstone(9).
+		Meta("owner", []byte("tests")).
+		Build(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := orderOf(t, b); got != "a2 b5 " {
+		t.Fatal(got)
+	}
+	if v := b.Meta().GetString("owner"); v != "tests" {
+		t.Fatal(v)
+	}
+	// Tombstones aren't given again
+	if seq, err := b.Put([]byte("c"), nil); err != nil || seq != 10 {
+		t.Fatal(seq, err)
+	}
+
+	// Bucket must be empty
+	if err := NewBuilder().Put(1, []byte("x"), nil).Build(b); !errors.Is(err, ErrNotEmpty) {
+		t.Fatal(err)
+	}
+}
+
+func TestBuilder_invalid(t *testing.T) {
+	for _, tc := range []struct {
+		bl  *Builder
+		err error
+	}{
+		{NewBuilder().Put(1, []byte("a"), nil).Put(1, []byte("b"), nil), ErrSeqExists},
+		{NewBuilder().Put(1, []byte("a"), nil).Tombstone(1), ErrSeqExists},
+		{NewBuilder().Put(1, []byte("a"), nil).Put(2, []byte("a"), nil), ErrKeyExists},
+		{NewBuilder().Put(0, []byte("a"), nil), ErrInvalidSeq},
+		{NewBuilder().Tombstone(seqExtBit), ErrInvalidSeq},
+		{NewBuilder().Put(1, nil, nil), ErrInvalidKey},
+	} {
+		b := NewMemBucket()
+		if err := tc.bl.Build(b); !errors.Is(err, tc.err) {
+			t.Fatal(err)
+		}
+		if b.ApproxCount() != 0 {
+			t.Fatal("bucket modified")
+		}
+	}
+}
+
+func TestBuilder_db(t *testing.T) {
+	bdb, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(bdb.Path())
+
+	db := NewDB(bdb)
+	path := [][]byte{testBucketName}
+	if err := NewBuilder().Put(7, []byte("a"), []byte("1")).BuildDB(db, path); err != nil {
+		t.Fatal(err)
+	}
+	err = db.ViewBucket(path, func(b *Bucket) error {
+		if got := orderOf(t, b); got != "a7 " {
+			t.Fatal(got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,93 @@
+package boltseq
+
+import (
+	"bytes"
+	"errors"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrUnsorted is returned by BulkLoader if entries are not sorted by key.
+var ErrUnsorted = errors.New("entries not sorted by key")
+
+// DefaultBulkBatchSize is the default value of BulkLoader.BatchSize.
+const DefaultBulkBatchSize = 10000
+
+// BulkLoader loads entries sorted by key into a bucket, committing them
+// in batches of separate transactions. Both sub-buckets are filled fully
+// as keys and sequences are added in order.
+type BulkLoader struct {
+	// BatchSize is the number of entries per transaction.
+	// Defaults to DefaultBulkBatchSize.
+	BatchSize int
+
+	db   *DB
+	path [][]byte
+
+	tx   *bolt.Tx
+	b    *Bucket
+	n    int
+	last []byte
+}
+
+// NewBulkLoader returns loader writing into bucket at path of db.
+func NewBulkLoader(db *DB, path [][]byte) *BulkLoader {
+	return &BulkLoader{db: db, path: path}
+}
+
+// Add adds the entry. Entries must be added in increasing key order.
+// Entries with zero Seq are given sequence numbers in order they are added,
+// otherwise the given sequence number is used. If the entry can't be stored,
+// entries added since the last commit are discarded, as by Rollback.
+func (l *BulkLoader) Add(e Entry) error {
+	if l.last != nil && bytes.Compare(e.Key, l.last) <= 0 {
+		return ErrUnsorted
+	}
+
+	if l.tx == nil {
+		tx, b, err := l.db.beginBucket(l.path)
+		if err != nil {
+			return err
+		}
+		b.bulk = true
+		l.tx, l.b = tx, b
+	}
+
+	if _, err := l.b.put(e.Key, e.Data, e.Seq); err != nil {
+		l.Rollback()
+		return err
+	}
+	l.last = append(l.last[:0], e.Key...)
+
+	batch := l.BatchSize
+	if batch <= 0 {
+		batch = DefaultBulkBatchSize
+	}
+	if l.n++; l.n >= batch {
+		return l.Flush()
+	}
+	return nil
+}
+
+// Flush commits entries added so far.
+func (l *BulkLoader) Flush() error {
+	if l.tx == nil {
+		return nil
+	}
+	err := l.tx.Commit()
+	if err == nil {
+		l.db.notify()
+	}
+	l.tx, l.b, l.n = nil, nil, 0
+	return err
+}
+
+// Rollback discards entries added since the last commit.
+func (l *BulkLoader) Rollback() error {
+	if l.tx == nil {
+		return nil
+	}
+	err := l.tx.Rollback()
+	l.tx, l.b, l.n = nil, nil, 0
+	return err
+}
@@ -0,0 +1,94 @@
+package boltseq
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestBulkLoader(t *testing.T) {
+	bdb, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(bdb.Path())
+
+	db := NewDB(bdb)
+	path := [][]byte{testBucketName}
+
+	l := NewBulkLoader(db, path)
+	l.BatchSize = 7
+	for n := 0; n < 20; n++ {
+		e := Entry{Key: []byte(fmt.Sprintf("%03d", n)), Data: []byte(fmt.Sprint(n))}
+		if n == 10 {
+			e.Seq = 100
+		}
+		if err := l.Add(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Add(Entry{Key: []byte("000")}); err != ErrUnsorted {
+		t.Fatal(err)
+	}
+	if err := l.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.ViewBucket(path, func(b *Bucket) error {
+		var seqs []uint64
+		err := b.ForEach(func(seq uint64, key, data []byte) error {
+			seqs = append(seqs, seq)
+			return nil
+		})
+		if s := fmt.Sprint(seqs); s != "[1 2 3 4 5 6 7 8 9 10 100 101 102 103 104 105 106 107 108 109]" {
+			t.Fatal(s)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBulkLoader_error(t *testing.T) {
+	bdb, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(bdb.Path())
+
+	db := NewDB(bdb)
+	path := [][]byte{testBucketName}
+
+	l := NewBulkLoader(db, path)
+	l.BatchSize = 3
+	for _, k := range []string{"a", "b", "c", "d"} {
+		if err := l.Add(Entry{Key: []byte(k)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Add(Entry{Key: []byte("e"), Seq: 1}); !errors.Is(err, ErrSeqExists) {
+		t.Fatal(err)
+	}
+	if l.tx != nil {
+		t.Fatal("transaction left open")
+	}
+
+	// Entries since the last commit are discarded, the loader can be reused
+	if err := l.Add(Entry{Key: []byte("f")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	err = db.ViewBucket(path, func(b *Bucket) error {
+		if s := orderOf(t, b); s != "a1 b2 c3 f4 " {
+			t.Fatal(s)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,163 @@
+package boltseq
+
+import (
+	"container/list"
+	"encoding/binary"
+	"sync"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// cache is an LRU cache of items read through DB, bounded by number of
+// entries and bytes. It's cleared on every commit done through DB.
+type cache struct {
+	mu         sync.Mutex
+	maxEntries int
+	maxBytes   int64
+	bytes      int64
+	gen        uint64 // incremented on clear
+	ll         *list.List
+	items      map[string]*list.Element
+}
+
+type cacheEntry struct {
+	key string
+	val []byte
+}
+
+func newCache(maxEntries int, maxBytes int64) *cache {
+	return &cache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+// get returns cached value and true, or current generation and false if
+// the key is not cached.
+func (c *cache) get(key string) ([]byte, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.ll.MoveToFront(e)
+		return e.Value.(*cacheEntry).val, c.gen, true
+	}
+	return nil, c.gen, false
+}
+
+// add caches value read at generation gen, unless the cache was cleared since.
+func (c *cache) add(key string, val []byte, gen uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen || c.items[key] != nil {
+		return
+	}
+	c.items[key] = c.ll.PushFront(&cacheEntry{key: key, val: val})
+	c.bytes += int64(len(key) + len(val))
+
+	for c.ll.Len() > 0 && ((c.maxEntries > 0 && c.ll.Len() > c.maxEntries) || (c.maxBytes > 0 && c.bytes > c.maxBytes)) {
+		e := c.ll.Back().Value.(*cacheEntry)
+		c.ll.Remove(c.ll.Back())
+		delete(c.items, e.key)
+		c.bytes -= int64(len(e.key) + len(e.val))
+	}
+}
+
+func (c *cache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gen++
+	c.ll.Init()
+	c.items = make(map[string]*list.Element)
+	c.bytes = 0
+}
+
+// cacheKey returns cache key for item of bucket at path, identified by kind and id.
+func cacheKey(path [][]byte, kind byte, id []byte) string {
+	var p []byte
+	var buf [binary.MaxVarintLen64]byte
+	for _, name := range path {
+		p = append(p, buf[:binary.PutUvarint(buf[:], uint64(len(name)))]...)
+		p = append(p, name...)
+	}
+	p = append(p, 0, kind)
+	return string(append(p, id...))
+}
+
+// EnableCache enables LRU cache of items read by Get and GetSeq, holding up
+// to maxEntries items and maxBytes bytes, zero meaning no limit. The cache is
+// cleared on every commit done through the DB; commits done otherwise, e.g.
+// through the underlying bolt.DB, aren't noticed. Items put with expiry
+// time aren't cached, so they're never served after they expire.
+func (db *DB) EnableCache(maxEntries int, maxBytes int64) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.cache = newCache(maxEntries, maxBytes)
+}
+
+func (db *DB) getCache() *cache {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.cache
+}
+
+// Get returns value for the key in bucket at path, or nil if it doesn't exist,
+// reading through the cache if enabled. Returned value is valid after the
+// transaction and must not be modified.
+func (db *DB) Get(path [][]byte, key []byte) (Value, error) {
+	v, err := db.cachedGet(cacheKey(path, 'k', key), path, func(b *Bucket) ([]byte, bool, error) {
+		v, err := b.GetValue(key)
+		return v.Clone(), !expiring(v), err
+	})
+	return Value(v), err
+}
+
+// GetSeq returns key with sequence number seq in bucket at path, or nil if it
+// doesn't exist, reading through the cache if enabled. Returned key is valid
+// after the transaction and must not be modified.
+func (db *DB) GetSeq(path [][]byte, seq uint64) ([]byte, error) {
+	return db.cachedGet(cacheKey(path, 's', seqKey(seq)), path, func(b *Bucket) ([]byte, bool, error) {
+		if k := b.GetSeq(seq); k != nil {
+			return append([]byte{}, k...), !expiring(b.get(k)), nil
+		}
+		return nil, true, nil
+	})
+}
+
+// expiring tells whether value v has expiry time.
+func expiring(v Value) bool {
+	h, _, ok := parseHeader(v)
+	return ok && h.flags&flagExpiry != 0
+}
+
+// cachedGet returns item read by get, which also tells whether it may be cached.
+func (db *DB) cachedGet(key string, path [][]byte, get func(b *Bucket) ([]byte, bool, error)) ([]byte, error) {
+	c := db.getCache()
+	var gen uint64
+	if c != nil {
+		var ok bool
+		var v []byte
+		if v, gen, ok = c.get(key); ok {
+			return v, nil
+		}
+	}
+
+	var v []byte
+	var cacheable bool
+	err := db.View(func(tx *bolt.Tx) error {
+		b, err := db.bucket(tx, path)
+		if err != nil {
+			return err
+		}
+		v, cacheable, err = get(b)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if c != nil && cacheable {
+		c.add(key, v, gen)
+	}
+	return v, nil
+}
@@ -0,0 +1,147 @@
+package boltseq
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestDBCache(t *testing.T) {
+	bdb, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(bdb.Path())
+
+	db := NewDB(bdb)
+	db.EnableCache(2, 0)
+	path := [][]byte{testBucketName}
+
+	err = db.UpdateBucket(path, func(b *Bucket) error {
+		if _, err := b.Put([]byte("a"), []byte("1")); err != nil {
+			return err
+		}
+		_, err := b.Put([]byte("b"), []byte("2"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(k string) string {
+		v, err := db.Get(path, []byte(k))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(v.Data())
+	}
+	if v := get("a"); v != "1" {
+		t.Fatal(v)
+	}
+	if k, err := db.GetSeq(path, 2); err != nil || string(k) != "b" {
+		t.Fatal(string(k), err)
+	}
+
+	// Writes bypassing the DB are not noticed
+	err = db.DB.Update(func(tx *bolt.Tx) error {
+		b, err := db.bucket(tx, path)
+		if err != nil {
+			return err
+		}
+		_, err = b.Put([]byte("a"), []byte("x"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := get("a"); v != "1" {
+		t.Fatal(v)
+	}
+
+	// Least recently used "b" by seq is evicted
+	get("b")
+	if len(db.cache.items) != 2 {
+		t.Fatal(len(db.cache.items))
+	}
+	if _, ok := db.cache.items[cacheKey(path, 's', seqKey(2))]; ok {
+		t.Fatal("not evicted")
+	}
+
+	// Writes through the DB clear the cache
+	err = db.UpdateBucket(path, func(b *Bucket) error {
+		_, err := b.Put([]byte("c"), []byte("3"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := get("a"); v != "x" {
+		t.Fatal(v)
+	}
+	if v := get("missing"); v != "" {
+		t.Fatal(v)
+	}
+
+	if _, err := db.Get([][]byte{[]byte("none")}, []byte("a")); err != bolt.ErrBucketNotFound {
+		t.Fatal(err)
+	}
+}
+
+func TestCacheMaxBytes(t *testing.T) {
+	c := newCache(0, 9)
+	c.add("a", []byte("1234"), 0)
+	c.add("b", []byte("1234"), 0)
+	if c.ll.Len() != 1 || c.bytes != 5 {
+		t.Fatal(c.ll.Len(), c.bytes)
+	}
+	if _, _, ok := c.get("b"); !ok {
+		t.Fatal("b not cached")
+	}
+
+	// Stale reads aren't cached
+	_, gen, _ := c.get("x")
+	c.clear()
+	c.add("x", nil, gen)
+	if _, _, ok := c.get("x"); ok {
+		t.Fatal("stale value cached")
+	}
+}
+
+func TestDBCache_ttl(t *testing.T) {
+	bdb, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(bdb.Path())
+
+	now := time.Unix(1000, 0)
+	db := NewDB(bdb)
+	db.Options.Now = func() time.Time { return now }
+	db.EnableCache(0, 0)
+	path := [][]byte{testBucketName}
+
+	err = db.UpdateBucket(path, func(b *Bucket) error {
+		_, err := b.PutTTL([]byte("a"), []byte("1"), time.Second)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := db.Get(path, []byte("a")); err != nil || string(v.Data()) != "1" {
+		t.Fatal(v, err)
+	}
+	if k, err := db.GetSeq(path, 1); err != nil || string(k) != "a" {
+		t.Fatal(k, err)
+	}
+
+	// Expired items aren't served from the cache
+	now = now.Add(time.Hour)
+	if v, err := db.Get(path, []byte("a")); err != nil || v != nil {
+		t.Fatal(v, err)
+	}
+	if k, err := db.GetSeq(path, 1); err != nil || k != nil {
+		t.Fatal(k, err)
+	}
+}
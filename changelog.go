@@ -0,0 +1,284 @@
+package boltseq
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+)
+
+var bucketNameLog = []byte("log")
+
+// log record flags
+const (
+	logHasOld byte = 1 << iota
+	logHasNew
+	logHasOldData
+)
+
+// LogRecord is a record of a modification kept in the change log.
+type LogRecord struct {
+	ID   uint64 // position in the log, increasing
+	Op   ChangeOp
+	Seq  uint64 // sequence number of the item after put, or of deleted item
+	Key  []byte
+	Time time.Time
+
+	// OldHash and NewHash are SHA-256 hashes of data before and after the
+	// modification, nil if the item didn't exist or was deleted, respectively.
+	OldHash []byte
+	NewHash []byte
+
+	// OldSeq and OldData are sequence number and data of the item before the
+	// modification, if it existed and Options.LogData is set. OldData is nil
+	// otherwise.
+	OldSeq  uint64
+	OldData []byte
+
+	// Origin is origin metadata of put items, see PutOrigin.
+	Origin []byte
+}
+
+// dataHash returns SHA-256 hash of data.
+func dataHash(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+// logState is the state of an item before a modification, as recorded in
+// the change log.
+type logState struct {
+	seq  uint64
+	hash []byte
+	data []byte // copy of data, if LogData is set
+}
+
+// logState returns state of the item with stored value v of the key, or nil
+// if v is nil.
+func (b *Bucket) logState(key []byte, v Value) (*logState, error) {
+	if v == nil {
+		return nil, nil
+	}
+	dv, err := b.decode(b.storeKey(key), v)
+	if err != nil {
+		return nil, err
+	}
+	return b.logData(v.Seq(), dv.Data()), nil
+}
+
+// logData returns state of an item with sequence number seq and data.
+func (b *Bucket) logData(seq uint64, data []byte) *logState {
+	s := &logState{seq: seq, hash: dataHash(data)}
+	if b.LogData {
+		s.data = append(make([]byte, 0, len(data)), data...)
+	}
+	return s
+}
+
+// logChange records modification of the item in the change log, if enabled,
+// and prunes records older than LogRetention. The old state is nil if the item
+// didn't exist.
+func (b *Bucket) logChange(op ChangeOp, seq uint64, key []byte, old *logState, newHash, origin []byte) error {
+	if !b.ChangeLog {
+		return nil
+	}
+	var oldHash, oldData []byte
+	if old != nil {
+		oldHash = old.hash
+	}
+	bl, err := b.createBucket(bucketNameLog)
+	if err != nil {
+		return err
+	}
+	id, err := bl.NextSequence()
+	if err != nil {
+		return err
+	}
+	now := b.now()
+
+	var flags byte
+	if oldHash != nil {
+		flags |= logHasOld
+	}
+	if newHash != nil {
+		flags |= logHasNew
+	}
+	if old != nil && old.data != nil {
+		flags |= logHasOldData
+		oldData = old.data
+	}
+	p := make([]byte, 18, 18+len(oldHash)+len(newHash)+8+binary.MaxVarintLen64+len(oldData)+1+len(origin)+len(key))
+	p[0] = byte(op)
+	binary.BigEndian.PutUint64(p[1:], seq)
+	binary.BigEndian.PutUint64(p[9:], uint64(now.UnixNano()))
+	p[17] = flags
+	p = append(p, oldHash...)
+	p = append(p, newHash...)
+	if flags&logHasOldData != 0 {
+		var buf [binary.MaxVarintLen64]byte
+		p = append(p, seqKey(old.seq)...)
+		p = append(p, buf[:binary.PutUvarint(buf[:], uint64(len(oldData)))]...)
+		p = append(p, oldData...)
+	}
+	p = append(p, byte(len(origin)))
+	p = append(p, origin...)
+	p = append(p, key...)
+	if err := bl.Put(seqKey(id), p); err != nil {
+		return err
+	}
+
+	if b.LogRetention <= 0 {
+		return nil
+	}
+	cutoff := now.Add(-b.LogRetention).UnixNano()
+	c := bl.Cursor()
+	for k, v := c.First(); k != nil && len(v) >= 17; k, v = c.First() {
+		if int64(binary.BigEndian.Uint64(v[9:])) >= cutoff {
+			break
+		}
+		if err := c.Delete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logDelete prepares recording deletion of stored value v of the key, which
+// needs to be hashed before it's released. Returned function records it.
+func (b *Bucket) logDelete(key []byte, v Value) (func() error, error) {
+	if !b.ChangeLog {
+		return func() error { return nil }, nil
+	}
+	old, err := b.logState(key, v)
+	if err != nil {
+		return nil, err
+	}
+	return func() error { return b.logChange(ChangeDelete, old.seq, key, old, nil, nil) }, nil
+}
+
+// logUpdate records the key getting sequence number seq and data of stored
+// value v of vkey, replacing data of the key's own value if vkey differs.
+func (b *Bucket) logUpdate(key, vkey []byte, v Value, seq uint64) error {
+	if !b.ChangeLog {
+		return nil
+	}
+	old, err := b.logState(vkey, v)
+	if err != nil {
+		return err
+	}
+	hash := old.hash
+	if !bytes.Equal(key, vkey) {
+		if old, err = b.logState(key, b.get(key)); err != nil {
+			return err
+		}
+	}
+	return b.logChange(ChangePut, seq, key, old, hash, v.Origin())
+}
+
+// parseLogRecord parses log record with the given id.
+func parseLogRecord(id uint64, p []byte) (LogRecord, bool) {
+	if len(p) < 19 {
+		return LogRecord{}, false
+	}
+	r := LogRecord{
+		ID:   id,
+		Op:   ChangeOp(p[0]),
+		Seq:  binary.BigEndian.Uint64(p[1:]),
+		Time: time.Unix(0, int64(binary.BigEndian.Uint64(p[9:]))),
+	}
+	flags := p[17]
+	p = p[18:]
+	for _, f := range []struct {
+		flag byte
+		hash *[]byte
+	}{{logHasOld, &r.OldHash}, {logHasNew, &r.NewHash}} {
+		if flags&f.flag == 0 {
+			continue
+		}
+		if len(p) < sha256.Size+1 {
+			return LogRecord{}, false
+		}
+		*f.hash, p = p[:sha256.Size], p[sha256.Size:]
+	}
+	if flags&logHasOldData != 0 {
+		if len(p) < 8 {
+			return LogRecord{}, false
+		}
+		r.OldSeq = binary.BigEndian.Uint64(p)
+		size, m := binary.Uvarint(p[8:])
+		if m <= 0 || uint64(len(p)-8-m) < size+1 {
+			return LogRecord{}, false
+		}
+		p = p[8+m:]
+		r.OldData, p = p[:size:size], p[size:]
+	}
+	n := int(p[0])
+	if len(p) < 1+n {
+		return LogRecord{}, false
+	}
+	if n > 0 {
+		r.Origin = p[1 : 1+n]
+	}
+	r.Key = p[1+n:]
+	return r, true
+}
+
+// LogCursor iterates records of the change log in order they were made.
+type LogCursor struct {
+	c   KVCursor
+	rec LogRecord
+	err error
+}
+
+// Log returns iterator over the change log, see Options.ChangeLog.
+func (b *Bucket) Log() *LogCursor {
+	lc := &LogCursor{}
+	if bl := b.bucket(bucketNameLog); bl != nil {
+		lc.c = bl.Cursor()
+	}
+	return lc
+}
+
+func (lc *LogCursor) move(f func() ([]byte, []byte)) bool {
+	if lc.err != nil {
+		return false
+	}
+	k, v := f()
+	if k == nil {
+		return false
+	}
+	id, ok := parseSeqKey(k)
+	if ok {
+		lc.rec, ok = parseLogRecord(id, v)
+	}
+	if !ok {
+		lc.err = ErrInvalidValue
+		return false
+	}
+	return true
+}
+
+// First moves cursor to the oldest record. Returns false if the log is empty.
+func (lc *LogCursor) First() bool { return lc.c != nil && lc.move(lc.c.First) }
+
+// Last moves cursor to the newest record. Returns false if the log is empty.
+func (lc *LogCursor) Last() bool { return lc.c != nil && lc.move(lc.c.Last) }
+
+// Next moves cursor to the next record. Returns false at the end of the log.
+func (lc *LogCursor) Next() bool { return lc.c != nil && lc.move(lc.c.Next) }
+
+// Prev moves cursor to the previous record. Returns false at the beginning of the log.
+func (lc *LogCursor) Prev() bool { return lc.c != nil && lc.move(lc.c.Prev) }
+
+// Seek moves cursor to the record with the given ID or the next one.
+// Returns false if there is no such record.
+func (lc *LogCursor) Seek(id uint64) bool {
+	return lc.c != nil && lc.move(func() ([]byte, []byte) { return lc.c.Seek(seqKey(id)) })
+}
+
+// Record returns the current record. Slices are only valid for the life of
+// the transaction.
+func (lc *LogCursor) Record() LogRecord { return lc.rec }
+
+// Err returns error, if any.
+func (lc *LogCursor) Err() error { return lc.err }
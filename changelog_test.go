@@ -0,0 +1,97 @@
+package boltseq
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func logOf(t *testing.T, b *Bucket) string {
+	var s string
+	lc := b.Log()
+	for ok := lc.First(); ok; ok = lc.Next() {
+		r := lc.Record()
+		op := "put"
+		if r.Op == ChangeDelete {
+			op = "del"
+		}
+		s += fmt.Sprintf("%d:%s %s%d", r.ID, op, r.Key, r.Seq)
+		if r.OldHash != nil {
+			s += " old"
+		}
+		if r.NewHash != nil {
+			s += " new"
+		}
+		if r.Origin != nil {
+			s += " " + string(r.Origin)
+		}
+		s += "\n"
+	}
+	if err := lc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestBucket_changeLog(t *testing.T) {
+	now := time.Unix(1000, 0)
+	b := NewMemBucket()
+	b.Now = func() time.Time { return now }
+	b.ChangeLog = true
+	b.Dedup = true
+	big := bytes.Repeat([]byte("x"), 100)
+
+	if _, err := b.Put([]byte("a"), []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.PutOrigin([]byte("b"), big, []byte("w1")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Put([]byte("a"), []byte("2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Rename([]byte("a"), []byte("c")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Touch([]byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Delete([]byte("b")); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `1:put a1 new
+2:put b2 new w1
+3:put a3 old new
+4:del a3 old
+5:put c3 new
+6:put b4 old new w1
+7:del b4 old
+`
+	if s := logOf(t, b); s != want {
+		t.Fatal(s)
+	}
+
+	lc := b.Log()
+	if !lc.Seek(3) {
+		t.Fatal("not found")
+	}
+	old := lc.Record().OldHash
+	if !lc.Prev() || !bytes.Equal(lc.Record().NewHash, dataHash(big)) {
+		t.Fatal(lc.Record())
+	}
+	if !lc.Prev() || !bytes.Equal(lc.Record().NewHash, old) || !lc.Record().Time.Equal(now) {
+		t.Fatal(lc.Record())
+	}
+
+	// Old records are pruned on writes
+	b.LogRetention = time.Minute
+	now = now.Add(time.Hour)
+	if err := b.SetFlags([]byte("c"), flagAcked); err != nil {
+		t.Fatal(err)
+	}
+	if s := logOf(t, b); s != "8:put c3 old new\n" {
+		t.Fatal(s)
+	}
+}
@@ -0,0 +1,183 @@
+package boltseq
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+var bucketNameChunk = []byte("chunk")
+
+// DefaultChunkSize is the chunk size used if Options.ChunkSize is negative.
+const DefaultChunkSize = 256 << 10
+
+// ErrChunkNotFound is returned when a chunk of an item's data is missing.
+var ErrChunkNotFound = errors.New("chunk not found")
+
+// chunkRefSize is the size of data of chunked values: id and total length.
+const chunkRefSize = 16
+
+// chunkSize returns size of chunks, or 0 if chunking is disabled.
+func (b *Bucket) chunkSize() int {
+	if b.ChunkSize < 0 {
+		return DefaultChunkSize
+	}
+	return b.ChunkSize
+}
+
+// chunkKey returns key of chunk n of data with the given id.
+func chunkKey(id uint64, n uint32) []byte {
+	k := make([]byte, 12)
+	binary.BigEndian.PutUint64(k, id)
+	binary.BigEndian.PutUint32(k[8:], n)
+	return k
+}
+
+// parseChunkRef returns id and total length of chunked data.
+func parseChunkRef(ref []byte) (id uint64, size int64, ok bool) {
+	if len(ref) != chunkRefSize {
+		return 0, 0, false
+	}
+	return binary.BigEndian.Uint64(ref), int64(binary.BigEndian.Uint64(ref[8:])), true
+}
+
+// storeChunks splits data into chunks and returns reference to them.
+func (b *Bucket) storeChunks(data []byte) ([]byte, error) {
+	bc, err := b.createBucket(bucketNameChunk)
+	if err != nil {
+		return nil, err
+	}
+	id, err := bc.NextSequence()
+	if err != nil {
+		return nil, err
+	}
+
+	setFillPercent(bc, 1)
+	size, total := b.chunkSize(), len(data)
+	for n := uint32(0); len(data) > 0; n++ {
+		p := data
+		if len(p) > size {
+			p = p[:size]
+		}
+		if err := bc.Put(chunkKey(id, n), p); err != nil {
+			return nil, err
+		}
+		data = data[len(p):]
+	}
+
+	ref := make([]byte, chunkRefSize)
+	binary.BigEndian.PutUint64(ref, id)
+	binary.BigEndian.PutUint64(ref[8:], uint64(total))
+	return ref, nil
+}
+
+// deleteChunks deletes chunks of data with the given reference.
+func (b *Bucket) deleteChunks(ref []byte) error {
+	id, _, ok := parseChunkRef(ref)
+	bc := b.bucket(bucketNameChunk)
+	if !ok || bc == nil {
+		return nil
+	}
+
+	// Seek again after every delete, as cursors may skip items following
+	// a deleted one.
+	prefix := chunkKey(id, 0)[:8]
+	c := bc.Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Seek(prefix) {
+		if err := c.Delete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadChunks returns data with the given reference reassembled from chunks.
+func (b *Bucket) loadChunks(ref []byte) ([]byte, error) {
+	r, err := b.chunkReader(ref)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, r.size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (b *Bucket) chunkReader(ref []byte) (*chunkReader, error) {
+	id, size, ok := parseChunkRef(ref)
+	if !ok {
+		return nil, ErrInvalidValue
+	}
+	bc := b.bucket(bucketNameChunk)
+	if bc == nil {
+		return nil, ErrChunkNotFound
+	}
+	return &chunkReader{bc: bc, id: id, size: size}, nil
+}
+
+// chunkReader reads chunked data one chunk at a time.
+type chunkReader struct {
+	bc   KVBucket
+	id   uint64
+	n    uint32
+	size int64 // bytes left
+	p    []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.p) == 0 {
+		if r.size == 0 {
+			return 0, io.EOF
+		}
+		r.p = r.bc.Get(chunkKey(r.id, r.n))
+		if len(r.p) == 0 || int64(len(r.p)) > r.size {
+			return 0, ErrChunkNotFound
+		}
+		r.size -= int64(len(r.p))
+		r.n++
+	}
+	n := copy(p, r.p)
+	r.p = r.p[n:]
+	return n, nil
+}
+
+func (r *chunkReader) Close() error { return nil }
+
+// GetReader returns reader of data for the key, reading chunked data one
+// chunk at a time, unless encrypted. The reader is valid for the life of the transaction only.
+// Returns ErrKeyNotFound if the key doesn't exist or has expired.
+func (b *Bucket) GetReader(key []byte) (io.ReadCloser, error) {
+	v := b.getLive(key)
+	if v == nil {
+		return nil, ErrKeyNotFound
+	}
+	h, n, ok := parseHeader(v)
+	if !ok {
+		return nil, ErrInvalidValue
+	}
+
+	var r io.ReadCloser
+	if h.flags&flagChunked != 0 && h.flags&flagEncrypted == 0 {
+		cr, err := b.chunkReader(v[n:])
+		if err != nil {
+			return nil, err
+		}
+		r = cr
+	} else {
+		data, err := b.payload(h, v[n:])
+		if err == nil && h.flags&flagEncrypted != 0 {
+			data, err = b.enc.decrypt(h.nonce, data, h.ad(b.storeKey(key), v.Seq()))
+		}
+		if err != nil {
+			return nil, err
+		}
+		r = io.NopCloser(bytes.NewReader(data))
+	}
+
+	if h.flags&flagCompressed != 0 {
+		return decompressReader(r)
+	}
+	return r, nil
+}
@@ -0,0 +1,101 @@
+package boltseq
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// chunkCount returns number of stored chunks.
+func chunkCount(loc *bolt.Bucket) (n int) {
+	loc.Bucket(bucketNameChunk).ForEach(func(k, v []byte) error {
+		n++
+		return nil
+	})
+	return
+}
+
+func TestBucket_chunked(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	big := make([]byte, 10000)
+	for i := range big {
+		big[i] = byte(i)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		loc := tx.Bucket(testBucketName)
+		b := NewBucket(loc)
+		b.ChunkSize = 1000
+		b.Quota = 1 << 20
+
+		if _, err := b.Put([]byte("a"), big); err != nil {
+			return err
+		}
+		if _, err := b.Put([]byte("b"), []byte("small")); err != nil {
+			return err
+		}
+		if n := chunkCount(loc); n != 10 {
+			t.Fatal(n)
+		}
+
+		if v := b.Get([]byte("a")); v.Seq() != 1 || !bytes.Equal(v.Data(), big) {
+			t.Fatal(len(v))
+		}
+		c := b.Cursor()
+		c.First()
+		if data, err := c.Data(); err != nil || !bytes.Equal(data, big) {
+			t.Fatal(len(data), err)
+		}
+
+		r, err := b.GetReader([]byte("a"))
+		if err != nil {
+			return err
+		}
+		if data, err := io.ReadAll(r); err != nil || !bytes.Equal(data, big) {
+			t.Fatal(len(data), err)
+		}
+		r, err = b.GetReader([]byte("b"))
+		if err != nil {
+			return err
+		}
+		if data, err := io.ReadAll(r); err != nil || string(data) != "small" {
+			t.Fatal(data, err)
+		}
+		if _, err := b.GetReader([]byte("x")); err != ErrKeyNotFound {
+			t.Fatal(err)
+		}
+
+		if size, _ := b.storedSize(); size < int64(len(big)) {
+			t.Fatal(size)
+		}
+
+		// Chunks are deleted on overwrite and delete
+		if _, err := b.Put([]byte("a"), big[:2500]); err != nil {
+			return err
+		}
+		if n := chunkCount(loc); n != 3 {
+			t.Fatal(n)
+		}
+		if err := b.Delete([]byte("a")); err != nil {
+			return err
+		}
+		if n := chunkCount(loc); n != 0 {
+			t.Fatal(n)
+		}
+		if size, _ := b.storedSize(); size != int64(len("b")+10+len("small")) {
+			t.Fatal(size)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,62 @@
+package boltseq
+
+// compactBatch is the number of items renumbered per cursor pass.
+const compactBatch = 1000
+
+// Compact renumbers all items with sequence numbers 1..N keeping their order,
+// and resets sequence counter to N. Note sequence numbers handed out before
+// get reused, so consumers tracking progress by sequence number must reset.
+func (b *Bucket) Compact() error {
+	if b.AppendOnly {
+		return ErrAppendOnly
+	}
+	bs := b.bucket(bucketNameSeq)
+	if bs == nil {
+		return nil
+	}
+
+	var n uint64
+	var next uint64 // old sequence number to continue from
+	for {
+		// Collect a batch first, as the bucket can't be modified while iterating
+		type item struct {
+			key []byte
+			seq uint64
+		}
+		var batch []item
+		c := b.rawCursor()
+		for ok := c.Seek(next); ok && len(batch) < compactBatch; ok = c.Next() {
+			batch = append(batch, item{key: append([]byte{}, c.Key()...), seq: c.Seq()})
+		}
+		if err := c.Err(); err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		for _, it := range batch {
+			n++
+			if it.seq == n {
+				continue
+			}
+
+			v := b.get(it.key)
+			if !v.IsValid() {
+				return ErrInvalidValue
+			}
+			if v.Seq() != it.seq {
+				return ErrSeqMismatch
+			}
+			if err := bs.Delete(v.seqBytes()); err != nil {
+				return err
+			}
+			if err := b.reseq(it.key, v, n); err != nil {
+				return err
+			}
+		}
+		next = batch[len(batch)-1].seq + 1
+	}
+
+	return bs.SetSequence(n)
+}
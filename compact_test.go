@@ -0,0 +1,44 @@
+package boltseq
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBucket_compact(t *testing.T) {
+	b := NewMemBucket()
+	for n := 0; n < 2500; n++ {
+		if _, err := b.Put([]byte(fmt.Sprint(n%1200)), []byte(fmt.Sprint(n))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := b.Delete([]byte("1199")); err != nil {
+		t.Fatal(err)
+	}
+
+	var before []string
+	b.ForEach(func(seq uint64, key, data []byte) error {
+		before = append(before, string(key)+"="+string(data))
+		return nil
+	})
+
+	if err := b.Compact(); err != nil {
+		t.Fatal(err)
+	}
+
+	n := 0
+	err := b.ForEach(func(seq uint64, key, data []byte) error {
+		if seq != uint64(n+1) || string(key)+"="+string(data) != before[n] {
+			t.Fatal(seq, string(key), before[n])
+		}
+		n++
+		return nil
+	})
+	if err != nil || n != 1199 {
+		t.Fatal(n, err)
+	}
+
+	if seq, _ := b.Put([]byte("new"), nil); seq != 1200 {
+		t.Fatal(seq)
+	}
+}
@@ -0,0 +1,46 @@
+package boltseq
+
+// Before tells whether v was put before other, i.e. has a lower sequence
+// number. Returns false if either value is not valid, e.g. nil for a missing key.
+func (v Value) Before(other Value) bool {
+	seq, ok := v.SeqOK()
+	oseq, ook := other.SeqOK()
+	return ok && ook && seq < oseq
+}
+
+// Newest returns the item with the highest sequence number among the given
+// keys. Missing keys are ignored; returns false if none of them exists.
+func (b *Bucket) Newest(keys ...[]byte) (Entry, bool, error) {
+	return b.pick(keys, func(v, cur Value) bool { return cur.Before(v) })
+}
+
+// Oldest returns the item with the lowest sequence number among the given
+// keys. Missing keys are ignored; returns false if none of them exists.
+func (b *Bucket) Oldest(keys ...[]byte) (Entry, bool, error) {
+	return b.pick(keys, Value.Before)
+}
+
+// pick returns the item of keys whose value is preferred over others by better.
+func (b *Bucket) pick(keys [][]byte, better func(v, cur Value) bool) (Entry, bool, error) {
+	var key []byte
+	var cur Value
+	for _, k := range keys {
+		v, err := b.GetValue(k)
+		if err != nil {
+			return Entry{}, false, err
+		}
+		if v == nil {
+			continue
+		}
+		if !v.IsValid() {
+			return Entry{}, false, opError("get", k, 0, ErrInvalidValue)
+		}
+		if cur == nil || better(v, cur) {
+			key, cur = k, v
+		}
+	}
+	if cur == nil {
+		return Entry{}, false, nil
+	}
+	return Entry{Seq: cur.Seq(), Key: key, Data: cur.Data()}, true, nil
+}
@@ -0,0 +1,37 @@
+package boltseq
+
+import "testing"
+
+func TestBucket_newest(t *testing.T) {
+	b := NewMemBucket()
+	for _, k := range []string{"a", "b", "c"} {
+		if _, err := b.Put([]byte(k), []byte(k+k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := b.Put([]byte("a"), []byte("aaa")); err != nil {
+		t.Fatal(err)
+	}
+
+	a, c := b.Get([]byte("a")), b.Get([]byte("c"))
+	if !c.Before(a) || a.Before(c) || a.Before(a) {
+		t.Fatal(a.Seq(), c.Seq())
+	}
+	if a.Before(nil) || Value(nil).Before(a) {
+		t.Fatal("missing value compared")
+	}
+
+	keys := [][]byte{[]byte("x"), []byte("c"), []byte("a"), []byte("b")}
+	if e, ok, err := b.Newest(keys...); err != nil || !ok || string(e.Key) != "a" || e.Seq != 4 || string(e.Data) != "aaa" {
+		t.Fatal(e, ok, err)
+	}
+	if e, ok, err := b.Oldest(keys...); err != nil || !ok || string(e.Key) != "b" || e.Seq != 2 {
+		t.Fatal(e, ok, err)
+	}
+	if _, ok, err := b.Newest([]byte("x"), []byte("y")); err != nil || ok {
+		t.Fatal(ok, err)
+	}
+	if _, ok, err := b.Oldest(); err != nil || ok {
+		t.Fatal(ok, err)
+	}
+}
@@ -0,0 +1,57 @@
+package boltseq
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+)
+
+// Compression tells whether Put compresses data.
+type Compression int
+
+const (
+	// CompressNone stores data as is.
+	CompressNone Compression = iota
+	// CompressAuto compresses data if it gets smaller.
+	CompressAuto
+	// CompressAlways compresses all data.
+	CompressAlways
+)
+
+// compress returns data compressed according to mode, or nil if data
+// should be stored as is.
+func compress(mode Compression, data []byte) ([]byte, error) {
+	if mode == CompressNone {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if mode == CompressAuto && buf.Len() >= len(data) {
+		return nil, nil
+	}
+	return buf.Bytes(), nil
+}
+
+func decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// decompressReader returns reader of decompressed data read from r.
+func decompressReader(r io.ReadCloser) (io.ReadCloser, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return zr, nil
+}
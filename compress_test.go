@@ -0,0 +1,74 @@
+package boltseq
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestBucket_compression(t *testing.T) {
+	b := NewMemBucket()
+	b.Compression = CompressAuto
+
+	text := bytes.Repeat([]byte("hello boltseq "), 100)
+	if _, err := b.Put([]byte("text"), text); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Put([]byte("short"), []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := b.get([]byte("text")); len(v) >= len(text) {
+		t.Fatal(len(v))
+	}
+	if v := b.get([]byte("short")); string(v.Data()) != "x" {
+		t.Fatal(v)
+	}
+	if v := b.Get([]byte("text")); v.Seq() != 1 || !bytes.Equal(v.Data(), text) {
+		t.Fatal(v)
+	}
+	c := b.Cursor()
+	c.First()
+	if data, err := c.Data(); err != nil || !bytes.Equal(data, text) {
+		t.Fatal(data, err)
+	}
+
+	// Always mode compresses even if data grows
+	b.Compression = CompressAlways
+	if _, err := b.Put([]byte("short"), []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	if v := b.get([]byte("short")); len(v) <= 9 {
+		t.Fatal(v)
+	}
+	if v := b.Get([]byte("short")); string(v.Data()) != "x" {
+		t.Fatal(v)
+	}
+
+	// Compressed data is readable when compression is turned off
+	b.Compression = CompressNone
+	if v := b.Get([]byte("text")); !bytes.Equal(v.Data(), text) {
+		t.Fatal(v)
+	}
+}
+
+func TestBucket_compressionChunked(t *testing.T) {
+	b := NewMemBucket()
+	b.Compression = CompressAlways
+	b.ChunkSize = 10
+
+	text := bytes.Repeat([]byte("hello boltseq "), 100)
+	if _, err := b.Put([]byte("text"), text); err != nil {
+		t.Fatal(err)
+	}
+	r, err := b.GetReader([]byte("text"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := io.ReadAll(r); err != nil || !bytes.Equal(data, text) {
+		t.Fatal(data, err)
+	}
+	if v := b.Get([]byte("text")); !bytes.Equal(v.Data(), text) {
+		t.Fatal(v)
+	}
+}
@@ -0,0 +1,49 @@
+package boltseq
+
+import "errors"
+
+// ErrAppendOnly is returned when modifying existing items of an append-only bucket.
+var ErrAppendOnly = errors.New("bucket is append-only")
+
+// OverwritePolicy tells what Put does when the key already exists.
+type OverwritePolicy int
+
+const (
+	// OverwriteResequence replaces the item, giving it the next sequence number.
+	OverwriteResequence OverwritePolicy = iota
+	// OverwriteKeepSeq replaces data of the item, keeping its sequence number.
+	OverwriteKeepSeq
+	// OverwriteReject fails with ErrKeyExists.
+	OverwriteReject
+)
+
+// GetOrPut returns data and sequence number of the key if it exists,
+// otherwise puts the value. Inserted tells whether the value was put, in which
+// case returned data is as stored, e.g. transformed by BeforePut hooks.
+func (b *Bucket) GetOrPut(key, value []byte) (data []byte, seq uint64, inserted bool, err error) {
+	v, err := b.GetValue(key)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	if v != nil {
+		return v.Data(), v.Seq(), false, nil
+	}
+
+	seq, err = b.Put(key, value)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	if v, err = b.GetValue(key); err != nil {
+		return nil, 0, false, err
+	}
+	return v.Data(), seq, true, nil
+}
+
+// PutIfAbsent puts the key-value pair if the key doesn't exist yet.
+// Returns ErrKeyExists otherwise. Expired items count as absent.
+func (b *Bucket) PutIfAbsent(key, value []byte) (uint64, error) {
+	if b.getLive(key) != nil {
+		return 0, opError("put", key, 0, ErrKeyExists)
+	}
+	return b.Put(key, value)
+}
@@ -0,0 +1,113 @@
+package boltseq
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBucket_getOrPut(t *testing.T) {
+	b := NewMemBucket()
+
+	data, seq, inserted, err := b.GetOrPut([]byte("a"), []byte("1"))
+	if err != nil || string(data) != "1" || seq != 1 || !inserted {
+		t.Fatal(data, seq, inserted, err)
+	}
+	data, seq, inserted, err = b.GetOrPut([]byte("a"), []byte("2"))
+	if err != nil || string(data) != "1" || seq != 1 || inserted {
+		t.Fatal(data, seq, inserted, err)
+	}
+	if s := orderOf(t, b); s != "a1 " {
+		t.Fatal(s)
+	}
+}
+
+func TestBucket_getOrPutHook(t *testing.T) {
+	b := NewMemBucket()
+	b.Compression = CompressAuto
+	b.BeforePut(func(key, value []byte) ([]byte, error) {
+		return append([]byte("<"), append(value, '>')...), nil
+	})
+
+	data, _, inserted, err := b.GetOrPut([]byte("a"), []byte("1"))
+	if err != nil || string(data) != "<1>" || !inserted {
+		t.Fatal(data, inserted, err)
+	}
+	data, _, inserted, err = b.GetOrPut([]byte("a"), []byte("2"))
+	if err != nil || string(data) != "<1>" || inserted {
+		t.Fatal(data, inserted, err)
+	}
+}
+
+func TestBucket_putIfAbsent(t *testing.T) {
+	b := NewMemBucket()
+	if seq, err := b.PutIfAbsent([]byte("a"), []byte("1")); err != nil || seq != 1 {
+		t.Fatal(seq, err)
+	}
+	if _, err := b.PutIfAbsent([]byte("a"), []byte("2")); !errors.Is(err, ErrKeyExists) {
+		t.Fatal(err)
+	}
+	if v := b.Get([]byte("a")); v.Seq() != 1 || string(v.Data()) != "1" {
+		t.Fatal(v)
+	}
+}
+
+func TestBucket_appendOnly(t *testing.T) {
+	b := NewMemBucket()
+	b.AppendOnly = true
+	if _, err := b.Put([]byte("a"), []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Put([]byte("b"), []byte("2")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := b.Put([]byte("a"), []byte("x")); !errors.Is(err, ErrAppendOnly) {
+		t.Fatal(err)
+	}
+	if err := b.Delete([]byte("a")); !errors.Is(err, ErrAppendOnly) {
+		t.Fatal(err)
+	}
+	if _, err := b.DeleteSeq(1); !errors.Is(err, ErrAppendOnly) {
+		t.Fatal(err)
+	}
+	if _, err := b.Touch([]byte("a")); !errors.Is(err, ErrAppendOnly) {
+		t.Fatal(err)
+	}
+	if err := b.Rename([]byte("a"), []byte("c")); !errors.Is(err, ErrAppendOnly) {
+		t.Fatal(err)
+	}
+	if s := orderOf(t, b); s != "a1 b2 " {
+		t.Fatal(s)
+	}
+}
+
+func TestBucket_overwritePolicy(t *testing.T) {
+	tests := []struct {
+		policy OverwritePolicy
+		order  string
+		data   string
+		err    error
+	}{
+		{OverwriteResequence, "b2 a3 ", "3", nil},
+		{OverwriteKeepSeq, "a1 b2 ", "3", nil},
+		{OverwriteReject, "a1 b2 ", "1", ErrKeyExists},
+	}
+	for _, tt := range tests {
+		b := NewMemBucket()
+		b.Overwrite = tt.policy
+		for _, kv := range []string{"a1", "b2"} {
+			if _, err := b.Put([]byte(kv[:1]), []byte(kv[1:])); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := b.Put([]byte("a"), []byte("3")); !errors.Is(err, tt.err) {
+			t.Errorf("%d: %v", tt.policy, err)
+		}
+		if s := orderOf(t, b); s != tt.order {
+			t.Errorf("%d: %s", tt.policy, s)
+		}
+		if v := b.Get([]byte("a")); string(v.Data()) != tt.data {
+			t.Errorf("%d: %s", tt.policy, v.Data())
+		}
+	}
+}
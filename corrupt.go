@@ -0,0 +1,42 @@
+package boltseq
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSeqMismatch means data entry for a key holds different sequence number
+// than the sequence entry pointing to it.
+var ErrSeqMismatch = errors.New("sequence mismatch")
+
+// CorruptionError describes a corrupted item found during iteration.
+// Seq is zero if the sequence entry itself is invalid.
+type CorruptionError struct {
+	Seq uint64
+	Key []byte
+	Err error
+}
+
+func (e *CorruptionError) Error() string {
+	return fmt.Sprintf("corrupted item seq=%d key=%q: %v", e.Seq, e.Key, e.Err)
+}
+
+func (e *CorruptionError) Unwrap() error {
+	return e.Err
+}
+
+// verify checks that the data entry of the current item points back to it.
+func (c *Cursor) verify() error {
+	v, ok := c.dp.Get(c.key)
+	if !ok {
+		return ErrInvalidKey
+	}
+	seq, ok := Value(v).SeqOK()
+	if !ok {
+		return ErrInvalidValue
+	}
+	if seq != c.seq {
+		return ErrSeqMismatch
+	}
+	return nil
+}
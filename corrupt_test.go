@@ -0,0 +1,84 @@
+package boltseq
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestBucket_onCorrupt(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		tb := tx.Bucket(testBucketName)
+		b := NewBucket(tb)
+		for _, k := range []string{"a", "b", "c", "d"} {
+			if _, err := b.Put([]byte(k), []byte(k)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		bs, bd := tb.Bucket(bucketNameSeq), DataBucket(tb)
+		bd.Put([]byte("a"), []byte("bad"))            // invalid value
+		bd.Delete([]byte("b"))                        // missing data
+		bd.Put([]byte("c"), newValue(9, []byte("c"))) // seq mismatch
+		bs.Put([]byte("bad"), []byte("x"))            // invalid seq key
+
+		var corrupt []error
+		b.OnCorrupt = func(err *CorruptionError) error {
+			corrupt = append(corrupt, err)
+			return nil
+		}
+
+		var keys string
+		err := b.ForEach(func(seq uint64, key, data []byte) error {
+			keys += string(key)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if keys != "d" {
+			t.Fatal(keys)
+		}
+
+		exp := []error{ErrInvalidValue, ErrInvalidKey, ErrSeqMismatch, ErrInvalidKey}
+		if len(corrupt) != len(exp) {
+			t.Fatal(corrupt)
+		}
+		for n := range exp {
+			if !errors.Is(corrupt[n], exp[n]) {
+				t.Fatal(n, corrupt[n])
+			}
+		}
+		if ce := corrupt[3].(*CorruptionError); string(ce.Key) != "x" {
+			t.Fatal(ce)
+		}
+
+		// Filtered iteration reports them too
+		corrupt, keys = nil, ""
+		err = b.ForEachFilter(&Filter{Prefix: []byte{}}, func(seq uint64, key, data []byte) error {
+			keys += string(key)
+			return nil
+		})
+		if err != nil || keys != "d" || len(corrupt) != len(exp) {
+			t.Fatal(err, keys, corrupt)
+		}
+
+		// Without callback iteration stops on the first problem
+		b.OnCorrupt = nil
+		if err := b.ForEach(func(uint64, []byte, []byte) error { return nil }); err != ErrInvalidValue {
+			t.Fatal(err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
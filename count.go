@@ -0,0 +1,71 @@
+package boltseq
+
+// ApproxCount returns number of items in the bucket without iterating them.
+// For bbolt buckets it's taken from page statistics of the sequence
+// sub-bucket, so it's exact for committed data, but doesn't account for
+// changes made in the current transaction.
+func (b *Bucket) ApproxCount() int {
+	bs := b.bucket(bucketNameSeq)
+	if bs == nil {
+		return 0
+	}
+	return keyCount(bs)
+}
+
+// CountRange returns number of items with sequence numbers between minSeq and
+// maxSeq, inclusive, reading only the sequence sub-bucket. Zero maxSeq means
+// no upper bound. Expired items not yet deleted are counted.
+func (b *Bucket) CountRange(minSeq, maxSeq uint64) (int, error) {
+	n := 0
+	err := b.scanSeqs(minSeq, maxSeq, func(seq uint64, key []byte) error {
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// BytesRange returns stored bytes of items with sequence numbers between
+// minSeq and maxSeq, inclusive, counted as by Size. Zero maxSeq means no
+// upper bound. Data entries are looked up for their sizes, but not decoded,
+// so chunks and blobs aren't read.
+func (b *Bucket) BytesRange(minSeq, maxSeq uint64) (int64, error) {
+	bd := b.bucket(bucketNameData)
+	if bd == nil {
+		return 0, nil
+	}
+	pd := pointer{c: bd.Cursor()}
+
+	var size int64
+	err := b.scanSeqs(minSeq, maxSeq, func(seq uint64, key []byte) error {
+		v, ok := pd.Get(key)
+		if !ok {
+			return opError("get", key, seq, ErrInvalidKey)
+		}
+		size += entrySize(key, v)
+		return nil
+	})
+	return size, err
+}
+
+// scanSeqs calls fn for entries of the sequence sub-bucket between minSeq
+// and maxSeq, inclusive, with zero maxSeq meaning no upper bound.
+func (b *Bucket) scanSeqs(minSeq, maxSeq uint64, fn func(seq uint64, key []byte) error) error {
+	bs := b.bucket(bucketNameSeq)
+	if bs == nil {
+		return nil
+	}
+	c := bs.Cursor()
+	for k, key := c.Seek(seqKey(minSeq)); k != nil; k, key = c.Next() {
+		seq, ok := parseSeqKey(k)
+		if !ok {
+			return opError("get", key, 0, ErrInvalidKey)
+		}
+		if maxSeq != 0 && seq > maxSeq {
+			break
+		}
+		if err := fn(seq, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package boltseq
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestBucket_approxCount(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		b := NewBucket(tx.Bucket(testBucketName))
+		if n := b.ApproxCount(); n != 0 {
+			t.Fatal(n)
+		}
+		for n := 0; n < 100; n++ {
+			if _, err := b.Put([]byte(fmt.Sprint(n)), nil); err != nil {
+				return err
+			}
+		}
+		return b.Delete([]byte("0"))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		if n := NewBucket(tx.Bucket(testBucketName)).ApproxCount(); n != 99 {
+			t.Fatal(n)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mb := NewMemBucket()
+	if _, err := mb.Put([]byte("a"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if n := mb.ApproxCount(); n != 1 {
+		t.Fatal(n)
+	}
+}
+
+func TestBucket_countRange(t *testing.T) {
+	b := NewMemBucket()
+	for _, k := range []string{"a", "b", "c", "d", "e"} {
+		if _, err := b.Put([]byte(k), []byte("xx")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := b.DeleteSeq(3); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		min, max uint64
+		n        int
+	}{
+		{0, 0, 4},
+		{2, 4, 2},
+		{3, 3, 0},
+		{5, 0, 1},
+		{6, 0, 0},
+	}
+	for _, tt := range tests {
+		n, err := b.CountRange(tt.min, tt.max)
+		if err != nil || n != tt.n {
+			t.Errorf("CountRange(%d, %d) = %d, %v, want %d", tt.min, tt.max, n, err, tt.n)
+		}
+		// Each item is 1-byte key and 10-byte header with 2 bytes of data
+		size, err := b.BytesRange(tt.min, tt.max)
+		if err != nil || size != int64(13*tt.n) {
+			t.Errorf("BytesRange(%d, %d) = %d, %v, want %d", tt.min, tt.max, size, err, 13*tt.n)
+		}
+	}
+
+	total, err := b.Size()
+	if size, _ := b.BytesRange(0, 0); err != nil || size != total {
+		t.Fatal(size, total, err)
+	}
+}
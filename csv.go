@@ -0,0 +1,123 @@
+package boltseq
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+)
+
+// CSVColumn is a column of CSV export.
+type CSVColumn int
+
+const (
+	CSVSeq  CSVColumn = iota // sequence number
+	CSVKey                   // key encoded with KeyEncoding
+	CSVData                  // data encoded with DataEncoding
+	// CSVTime is sequence number as Unix time in nanoseconds, see TimeSeq.
+	CSVTime
+	// CSVExpires is expiry time of items put with PutTTL, empty for others.
+	CSVExpires
+)
+
+var csvColumnNames = []string{"seq", "key", "data", "time", "expires"}
+
+// ErrInvalidColumn is returned by ExportCSV for unknown columns.
+var ErrInvalidColumn = errors.New("invalid CSV column")
+
+// CSVEncoding tells how binary keys and data are written in CSV export.
+type CSVEncoding int
+
+const (
+	CSVString CSVEncoding = iota
+	CSVHex
+	CSVBase64
+)
+
+func (e CSVEncoding) encode(p []byte) string {
+	switch e {
+	case CSVHex:
+		return hex.EncodeToString(p)
+	case CSVBase64:
+		return base64.StdEncoding.EncodeToString(p)
+	}
+	return string(p)
+}
+
+// CSVOptions configures CSV export.
+type CSVOptions struct {
+	// Columns to write, seq, key and data if empty.
+	Columns []CSVColumn
+
+	KeyEncoding  CSVEncoding
+	DataEncoding CSVEncoding
+
+	// Header enables writing column names in the first row.
+	Header bool
+}
+
+// ExportCSV writes items of the bucket to w as CSV in sequence order.
+// Times are formatted as RFC 3339 in UTC.
+func (b *Bucket) ExportCSV(w io.Writer, opts CSVOptions) error {
+	cols := opts.Columns
+	if len(cols) == 0 {
+		cols = []CSVColumn{CSVSeq, CSVKey, CSVData}
+	}
+	for _, col := range cols {
+		if col < 0 || int(col) >= len(csvColumnNames) {
+			return ErrInvalidColumn
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	row := make([]string, len(cols))
+	if opts.Header {
+		for n, col := range cols {
+			row[n] = csvColumnNames[col]
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	c := b.Cursor()
+	for ok := c.First(); ok; ok = c.Next() {
+		data, err := c.Data()
+		if err != nil {
+			return err
+		}
+		for n, col := range cols {
+			switch col {
+			case CSVSeq:
+				row[n] = strconv.FormatUint(c.Seq(), 10)
+			case CSVKey:
+				row[n] = opts.KeyEncoding.encode(c.Key())
+			case CSVData:
+				row[n] = opts.DataEncoding.encode(data)
+			case CSVTime:
+				row[n] = formatCSVTime(int64(c.Seq()))
+			case CSVExpires:
+				row[n] = ""
+				if h, _, ok := parseHeader(b.get(c.Key())); ok && h.flags&flagExpiry != 0 {
+					row[n] = formatCSVTime(h.expires)
+				}
+			}
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	if err := c.Err(); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatCSVTime(ns int64) string {
+	return time.Unix(0, ns).UTC().Format(time.RFC3339Nano)
+}
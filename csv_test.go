@@ -0,0 +1,52 @@
+package boltseq
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestBucket_exportCSV(t *testing.T) {
+	b := NewMemBucket()
+	b.Now = func() time.Time { return time.Unix(0, 0) }
+	if _, err := b.Put([]byte("a"), []byte("x,y")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.PutTTL([]byte{0xff}, []byte{0}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := b.ExportCSV(&buf, CSVOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if s := buf.String(); s != "1,a,\"x,y\"\n2,\xff,\x00\n" {
+		t.Fatalf("%q", s)
+	}
+
+	buf.Reset()
+	err := b.ExportCSV(&buf, CSVOptions{
+		Columns:      []CSVColumn{CSVKey, CSVData, CSVTime, CSVExpires},
+		KeyEncoding:  CSVHex,
+		DataEncoding: CSVBase64,
+		Header:       true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := "key,data,time,expires\n" +
+		"61,eCx5,1970-01-01T00:00:00.000000001Z,\n" +
+		"ff,AA==,1970-01-01T00:00:00.000000002Z,1970-01-01T01:00:00Z\n"
+	if s := buf.String(); s != exp {
+		t.Fatalf("%q", s)
+	}
+
+	// Unknown columns are rejected before anything is written
+	for _, col := range []CSVColumn{-1, CSVExpires + 1} {
+		buf.Reset()
+		err := b.ExportCSV(&buf, CSVOptions{Columns: []CSVColumn{CSVKey, col}, Header: true})
+		if err != ErrInvalidColumn || buf.Len() != 0 {
+			t.Fatal(col, err, buf.String())
+		}
+	}
+}
@@ -2,12 +2,12 @@ package boltseq
 
 import (
 	"bytes"
-
-	bolt "go.etcd.io/bbolt"
+	"time"
 )
 
+// pointer is a cursor over the data bucket remembering its position.
 type pointer struct {
-	c   *bolt.Cursor
+	c   KVCursor
 	key []byte
 	val []byte
 }
@@ -16,9 +16,23 @@ func (p *pointer) move(k []byte) bool {
 	if p.c == nil {
 		return false
 	}
-	if bytes.Equal(p.key, k) {
-		return true
+
+	// Keys are often added in key order, so iterating by sequence visits
+	// neighbouring data items. Try stepping before doing a full seek.
+	if p.key != nil {
+		switch bytes.Compare(k, p.key) {
+		case 0:
+			return true
+		case 1:
+			p.key, p.val = p.c.Next()
+		case -1:
+			p.key, p.val = p.c.Prev()
+		}
+		if bytes.Equal(p.key, k) {
+			return true
+		}
 	}
+
 	p.key, p.val = p.c.Seek(k)
 	return bytes.Equal(p.key, k)
 }
@@ -34,32 +48,134 @@ func (p *pointer) Delete(k []byte) error {
 	if !p.move(k) {
 		return nil
 	}
+	p.key, p.val = nil, nil
 	return p.c.Delete()
 }
 
 // Cursors allows for iterating buckets according to sequence number.
 type Cursor struct {
-	cs *bolt.Cursor
+	cs KVCursor
 	dp pointer
 
 	seq uint64
 	key []byte
 
-	err error
+	err    error
+	b      *Bucket
+	m      *merger // set for cursors merging other cursors
+	filter *Filter
+	ttl    bool // skip expired items
+	rev    bool // last moved backwards
+	opts   CursorOptions
+	n      int // items visited since First or Seek, for opts.Limit
+}
+
+// CursorOptions configure iteration of a cursor. Cursors don't read data of
+// items outside of bounds.
+type CursorOptions struct {
+	// Min and Max bound sequence numbers of visited items, inclusive.
+	// Zero Max means no upper bound.
+	Min, Max uint64
+
+	// Reverse makes First, Next and Seek move towards lower sequence
+	// numbers, and Last and Prev towards higher ones. Seek moves to the
+	// item at the sequence number or the nearest lower one.
+	Reverse bool
+
+	// Limit is the maximum number of items visited by First or Seek and
+	// subsequent calls to Next. Zero means no limit.
+	Limit int
+
+	// KeysOnly makes the cursor move using the sequence sub-bucket only,
+	// so expired items are not skipped. Data is read on demand by LoadData.
+	// Filters on flags or data still read the data sub-bucket.
+	KeysOnly bool
+}
+
+// CursorOpts returns iterator over the bucket configured by opts.
+func (b *Bucket) CursorOpts(opts CursorOptions) *Cursor {
+	c := b.Cursor()
+	c.opts = opts
+	return c
+}
+
+// step performs a single cursor move and reports it to metrics, if set.
+// Corrupted items are passed to OnCorrupt, if set, and skipped by calling skip.
+func (c *Cursor) step(move, skip func() ([]byte, []byte)) bool {
+	var start time.Time
+	if c.b.Metrics != nil {
+		start = time.Now()
+	}
+
+	prevSeq, prevKey := c.seq, c.key
+	seq, key := move()
+	var ok bool
+	for {
+		for c.skipCorrupt(seq, key) {
+			seq, key = skip()
+		}
+		if ok = c.sync(seq, key); ok && !c.inBounds() {
+			// Stay at the previous item, so reverse move continues from it
+			c.seq, c.key, ok = prevSeq, prevKey, false
+			if prevKey != nil {
+				c.cs.Seek(seqKey(prevSeq))
+			}
+		}
+		if !ok || c.visible() {
+			break
+		}
+		seq, key = skip()
+	}
+
+	if c.b.Metrics != nil {
+		n := 0
+		if ok {
+			n = len(c.key)
+		}
+		c.b.Metrics.ObserveCursorStep(time.Since(start), n)
+	}
+	return ok
+}
+
+// inBounds tells whether the current item is within bounds set by options.
+func (c *Cursor) inBounds() bool {
+	return c.seq >= c.opts.Min && (c.opts.Max == 0 || c.seq <= c.opts.Max)
+}
+
+// visible tells whether the current item is not expired and matches the filter.
+func (c *Cursor) visible() bool {
+	if c.ttl && !c.opts.KeysOnly {
+		if v, ok := c.dp.Get(c.key); ok && c.b.expired(v) {
+			return false
+		}
+	}
+	return c.filter == nil || c.filter.match(c)
+}
+
+// skipCorrupt tells whether item with invalid seq should be skipped.
+func (c *Cursor) skipCorrupt(seq []byte, key []byte) bool {
+	if _, ok := parseSeqKey(seq); seq == nil || ok || c.b.OnCorrupt == nil {
+		return false
+	}
+	if err := c.b.OnCorrupt(&CorruptionError{Key: key, Err: ErrInvalidKey}); err != nil {
+		c.err = err
+		return false
+	}
+	return true
 }
 
 func (c *Cursor) sync(seq []byte, key []byte) bool {
-	if seq == nil {
+	if seq == nil || c.err != nil {
 		return false
 	}
 
-	v := Value(seq)
-	if !v.IsValid() {
+	n, ok := parseSeqKey(seq)
+	if !ok {
 		c.err = ErrInvalidKey
 		return false
 	}
 
-	c.seq = v.Seq()
+	c.seq = n
 	c.key = key
 	return true
 }
@@ -67,56 +183,203 @@ func (c *Cursor) sync(seq []byte, key []byte) bool {
 // First moves cursor to the first key/value pair.
 // Returns false on empty bucket, true otherwise.
 func (c *Cursor) First() bool {
-	if c.cs == nil {
-		return false
+	c.n = 0
+	if c.opts.Reverse {
+		return c.count(c.last())
 	}
-
-	return c.sync(c.cs.First())
+	return c.count(c.first())
 }
 
 // Last moves cursor to the last key/value pair.
 // Returns false on empty bucket, true otherwise.
 func (c *Cursor) Last() bool {
-	if c.cs == nil {
-		return false
+	if c.opts.Reverse {
+		return c.first()
 	}
-
-	return c.sync(c.cs.Last())
+	return c.last()
 }
 
 // Next moves cursor to the next key/value pair.
 // Returns false is reached end of the bucket, true otherwise.
 func (c *Cursor) Next() bool {
-	if c.cs == nil {
+	if c.opts.Limit > 0 && c.n >= c.opts.Limit {
 		return false
 	}
-
-	return c.sync(c.cs.Next())
+	if c.opts.Reverse {
+		return c.count(c.prev())
+	}
+	return c.count(c.next())
 }
 
 // Prev moves cursor to the previous key/value pair.
 // Returns false is reached end of the bucket, true otherwise.
 func (c *Cursor) Prev() bool {
-	if c.cs == nil {
-		return false
+	if c.opts.Reverse {
+		return c.next()
 	}
-
-	return c.sync(c.cs.Prev())
+	return c.prev()
 }
 
 // Seek moves cursor to the key/value pair at the given seq number.
 // If seq number doesn't exists it points to the next item, if any.
 // Returns false if no item, true otherwise.
 func (c *Cursor) Seek(seq uint64) bool {
+	c.n = 0
+	if c.opts.Reverse {
+		return c.count(c.seekBack(seq))
+	}
+	return c.count(c.seek(seq))
+}
+
+// count counts visited item for the limit.
+func (c *Cursor) count(ok bool) bool {
+	if ok {
+		c.n++
+	}
+	return ok
+}
+
+// first moves cursor to the item with the lowest sequence number within bounds.
+func (c *Cursor) first() bool {
+	if c.opts.Min > 0 {
+		return c.seek(c.opts.Min)
+	}
+	c.rev = false
+	if c.m != nil {
+		return c.m.first(c)
+	}
+	if c.cs == nil {
+		return false
+	}
+
+	return c.step(c.cs.First, c.cs.Next)
+}
+
+// last moves cursor to the item with the highest sequence number within bounds.
+func (c *Cursor) last() bool {
+	if c.opts.Max > 0 {
+		return c.seekBack(c.opts.Max)
+	}
+	c.rev = true
+	if c.m != nil {
+		return c.m.last(c)
+	}
+	if c.cs == nil {
+		return false
+	}
+
+	return c.step(c.cs.Last, c.cs.Prev)
+}
+
+func (c *Cursor) next() bool {
+	c.rev = false
+	if c.m != nil {
+		return c.m.next(c)
+	}
+	if c.cs == nil {
+		return false
+	}
+
+	return c.step(c.cs.Next, c.cs.Next)
+}
+
+func (c *Cursor) prev() bool {
+	c.rev = true
+	if c.m != nil {
+		return c.m.prev(c)
+	}
+	if c.cs == nil {
+		return false
+	}
+
+	return c.step(c.cs.Prev, c.cs.Prev)
+}
+
+// seek moves cursor to the item at seq or the nearest higher one.
+func (c *Cursor) seek(seq uint64) bool {
+	if seq < c.opts.Min {
+		seq = c.opts.Min
+	}
+	c.rev = false
+	if c.m != nil {
+		return c.m.seek(c, seq)
+	}
+	if c.cs == nil {
+		return false
+	}
+
+	return c.step(func() ([]byte, []byte) {
+		return c.cs.Seek(seqKey(seq))
+	}, c.cs.Next)
+}
+
+// seekBack moves cursor to the item at seq or the nearest lower one.
+func (c *Cursor) seekBack(seq uint64) bool {
+	if c.opts.Max > 0 && seq > c.opts.Max {
+		seq = c.opts.Max
+	}
+	if c.m != nil {
+		if c.m.seek(c, seq) {
+			c.rev = true
+			return c.seq == seq || c.m.prev(c)
+		}
+		c.rev = true
+		return c.m.err() == nil && c.m.last(c)
+	}
+	c.rev = true
+	if c.cs == nil {
+		return false
+	}
+
+	return c.step(func() ([]byte, []byte) {
+		k, v := c.cs.Seek(seqKey(seq))
+		if k == nil {
+			return c.cs.Last()
+		}
+		if n, ok := parseSeqKey(k); ok && n > seq {
+			return c.cs.Prev()
+		}
+		return k, v
+	}, c.cs.Prev)
+}
+
+// SeekKey moves cursor to the key/value pair with the given key, so iteration
+// can continue in sequence order from that item onward.
+// Returns false if key doesn't exist, true otherwise.
+func (c *Cursor) SeekKey(key []byte) bool {
+	if c.m != nil {
+		return c.m.seekKey(c, key)
+	}
 	if c.cs == nil {
 		return false
 	}
 
-	return c.sync(c.cs.Seek((newValue(seq, nil).seqBytes())))
+	key = c.b.storeKey(key)
+	v, ok := c.dp.Get(key)
+	if !ok || (c.ttl && c.b.expired(v)) {
+		return false
+	}
+	seq, ok := Value(v).SeqOK()
+	if !ok {
+		c.err = ErrInvalidValue
+		return false
+	}
+
+	if seq < c.opts.Min || (c.opts.Max > 0 && seq > c.opts.Max) || !c.seek(seq) {
+		return false
+	}
+	if c.seq != seq || !bytes.Equal(c.key, key) {
+		c.err = ErrSeqMismatch
+		return false
+	}
+	return true
 }
 
 // Err returns error, if any.
 func (c *Cursor) Err() error {
+	if c.m != nil {
+		return c.m.err()
+	}
 	return c.err
 }
 
@@ -127,11 +390,29 @@ func (c *Cursor) Seq() uint64 {
 
 // Key returns current key.
 func (c *Cursor) Key() []byte {
-	return c.key
+	key := c.key
+	if c.b != nil && key != nil {
+		key = c.b.userKey(key)
+		if c.b.Detach {
+			key = append([]byte{}, key...)
+		}
+	}
+	return key
 }
 
 // Data returns current data for the key.
 func (c *Cursor) Data() ([]byte, error) {
+	if c.m != nil {
+		if cur := c.m.current(); cur != nil {
+			return cur.Data()
+		}
+		return nil, ErrInvalidKey
+	}
+
+	if c.key == nil {
+		return nil, ErrInvalidKey
+	}
+
 	v, ok := c.dp.Get(c.key)
 	if !ok {
 		return nil, ErrInvalidKey
@@ -142,15 +423,86 @@ func (c *Cursor) Data() ([]byte, error) {
 		return nil, ErrInvalidValue
 	}
 
+	val, err := c.b.decode(c.key, val)
+	if err != nil {
+		return nil, err
+	}
+	if c.b.Detach {
+		return val.DataCopy(), nil
+	}
 	return val.Data(), nil
 }
 
+// LoadData returns data of the current item, like Data, but returns ErrExpired
+// for expired items, which KeysOnly cursors don't skip.
+func (c *Cursor) LoadData() ([]byte, error) {
+	if c.m != nil {
+		if cur := c.m.current(); cur != nil {
+			return cur.LoadData()
+		}
+		return nil, ErrInvalidKey
+	}
+
+	if c.ttl && c.key != nil {
+		if v, ok := c.dp.Get(c.key); ok && c.b.expired(v) {
+			return nil, ErrExpired
+		}
+	}
+	return c.Data()
+}
+
 // Delete deletes the current item.
 func (c *Cursor) Delete() error {
-	err := c.dp.Delete(c.key)
+	if c.m != nil {
+		if cur := c.m.current(); cur != nil {
+			return cur.Delete()
+		}
+		return nil
+	}
+
+	if c.b.AppendOnly {
+		return ErrAppendOnly
+	}
+	if c.b.Metrics != nil {
+		defer observe(c.b.Metrics.ObserveDelete, time.Now(), len(c.key))
+	}
+
+	key := c.b.userKey(c.key)
+	if err := c.b.runBeforeDelete(key); err != nil {
+		return err
+	}
+
+	v, _ := c.dp.Get(c.key)
+	size := entrySize(c.key, v)
+	log, err := c.b.logDelete(key, v)
 	if err != nil {
 		return err
 	}
 
-	return c.cs.Delete()
+	if err := c.b.release(v); err != nil {
+		return err
+	}
+
+	if err := c.dp.Delete(c.key); err != nil {
+		return err
+	}
+	if err := c.b.deleteKey(c.key); err != nil {
+		return err
+	}
+
+	if err := c.cs.Delete(); err != nil {
+		return err
+	}
+
+	if err := c.b.growSize(-size); err != nil {
+		return err
+	}
+	if err := c.b.verify([][]byte{c.key}, c.seq); err != nil {
+		return err
+	}
+	if err := log(); err != nil {
+		return err
+	}
+
+	return c.b.runAfterDelete(key)
 }
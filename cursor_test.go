@@ -0,0 +1,158 @@
+package boltseq
+
+import (
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestCursor_seekKey(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		b := NewBucket(tx.Bucket(testBucketName))
+		for _, k := range []string{"c", "a", "b", "d"} {
+			if _, err := b.Put([]byte(k), nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		c := b.Cursor()
+		if c.SeekKey([]byte("nx")) {
+			t.Fatal(c.Key())
+		}
+
+		var keys string
+		for ok := c.SeekKey([]byte("a")); ok; ok = c.Next() {
+			keys += string(c.Key())
+		}
+		if keys != "abd" {
+			t.Fatal(keys)
+		}
+		return c.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCursor_entry(t *testing.T) {
+	b := NewMemBucket()
+	if _, err := b.Put([]byte("x"), []byte("v")); err != nil {
+		t.Fatal(err)
+	}
+
+	c := b.Cursor()
+	if _, err := c.Entry(); !errors.Is(err, ErrInvalidKey) {
+		t.Fatal(err)
+	}
+	if !c.First() {
+		t.Fatal(c.Err())
+	}
+	e, err := c.Entry()
+	if err != nil || e.Seq != 1 || string(e.Key) != "x" || string(e.Data) != "v" {
+		t.Fatal(e, err)
+	}
+}
+
+func benchmarkCursorData(b *testing.B, key func(n int) []byte) {
+	db, err := newTestDB()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	const count = 10000
+	err = db.Update(func(tx *bolt.Tx) error {
+		bb := NewBucket(tx.Bucket(testBucketName))
+		for n := 0; n < count; n++ {
+			if _, err := bb.Put(key(n), []byte("data")); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	err = db.View(func(tx *bolt.Tx) error {
+		for i := 0; i < b.N; i++ {
+			c := NewBucket(tx.Bucket(testBucketName)).Cursor()
+			for ok := c.First(); ok; ok = c.Next() {
+				if _, err := c.Data(); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+}
+
+func BenchmarkCursor_dataOrdered(b *testing.B) {
+	benchmarkCursorData(b, func(n int) []byte { return []byte(fmt.Sprintf("%08d", n)) })
+}
+
+func BenchmarkCursor_dataRandom(b *testing.B) {
+	benchmarkCursorData(b, func(n int) []byte {
+		h := sha1.Sum([]byte(fmt.Sprint(n)))
+		return h[:]
+	})
+}
+
+func TestBucket_getSeqEntry(t *testing.T) {
+	b := NewMemBucket()
+	for _, k := range []string{"a", "b"} {
+		if _, err := b.Put([]byte(k), []byte("v"+k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if k, d, err := b.GetSeqEntry(2); err != nil || string(k) != "b" || string(d) != "vb" {
+		t.Fatal(k, d, err)
+	}
+	if _, _, err := b.GetSeqEntry(3); !errors.Is(err, ErrSeqNotFound) {
+		t.Fatal(err)
+	}
+
+	bd := b.bucket(bucketNameData)
+	bd.Put([]byte("a"), newValue(5, nil))
+	if _, _, err := b.GetSeqEntry(1); !errors.Is(err, ErrSeqMismatch) {
+		t.Fatal(err)
+	}
+	bd.Delete([]byte("b"))
+	if _, _, err := b.GetSeqEntry(2); !errors.Is(err, ErrInvalidKey) {
+		t.Fatal(err)
+	}
+}
+
+func TestBucket_firstLast(t *testing.T) {
+	b := NewMemBucket()
+	if _, ok, err := b.Last(); ok || err != nil {
+		t.Fatal(ok, err)
+	}
+
+	for _, k := range []string{"a", "b", "c"} {
+		if _, err := b.Put([]byte(k), []byte("v"+k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if e, ok, err := b.First(); !ok || err != nil || e.Seq != 1 || string(e.Key) != "a" {
+		t.Fatal(e, ok, err)
+	}
+	if e, ok, err := b.Last(); !ok || err != nil || e.Seq != 3 || string(e.Data) != "vc" {
+		t.Fatal(e, ok, err)
+	}
+}
@@ -0,0 +1,118 @@
+package boltseq
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBucket_CursorOpts(t *testing.T) {
+	b := NewMemBucket()
+	for i := 0; i < 10; i++ {
+		if _, err := b.Put([]byte{'a' + byte(i)}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := b.DeleteSeq(5); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		opts CursorOptions
+		fwd  string
+		seek string // iteration from Seek(5)
+		back string // Last, then Prev
+	}{
+		{CursorOptions{}, "1 2 3 4 6 7 8 9 10 ", "6 7 8 9 10 ", "10 9 8 7 6 4 3 2 1 "},
+		{CursorOptions{Min: 3, Max: 8}, "3 4 6 7 8 ", "6 7 8 ", "8 7 6 4 3 "},
+		{CursorOptions{Min: 6}, "6 7 8 9 10 ", "6 7 8 9 10 ", "10 9 8 7 6 "},
+		{CursorOptions{Limit: 3}, "1 2 3 ", "6 7 8 ", "10 9 8 7 6 4 3 2 1 "},
+		{CursorOptions{Reverse: true}, "10 9 8 7 6 4 3 2 1 ", "4 3 2 1 ", "1 2 3 4 6 7 8 9 10 "},
+		{CursorOptions{Reverse: true, Min: 2, Max: 5, Limit: 2}, "4 3 ", "4 3 ", "2 3 4 "},
+		{CursorOptions{Min: 11}, "", "", ""},
+	}
+	for _, tt := range tests {
+		collect := func(first func(c *Cursor) bool, next func(c *Cursor) bool) string {
+			c := b.CursorOpts(tt.opts)
+			var s string
+			for ok := first(c); ok; ok = next(c) {
+				s += fmt.Sprintf("%d ", c.Seq())
+			}
+			if err := c.Err(); err != nil {
+				t.Fatal(err)
+			}
+			return s
+		}
+		if s := collect((*Cursor).First, (*Cursor).Next); s != tt.fwd {
+			t.Errorf("%+v: forward %q, want %q", tt.opts, s, tt.fwd)
+		}
+		seek := func(c *Cursor) bool { return c.Seek(5) }
+		if s := collect(seek, (*Cursor).Next); s != tt.seek {
+			t.Errorf("%+v: seek %q, want %q", tt.opts, s, tt.seek)
+		}
+		if s := collect((*Cursor).Last, (*Cursor).Prev); s != tt.back {
+			t.Errorf("%+v: backward %q, want %q", tt.opts, s, tt.back)
+		}
+	}
+
+	// Past the bound the cursor stays at the last item within bounds
+	c := b.CursorOpts(CursorOptions{Max: 3})
+	if !c.Seek(3) || c.Next() || c.Seq() != 3 || string(c.Key()) != "c" {
+		t.Fatal(c.Seq(), string(c.Key()))
+	}
+	if !c.Prev() || c.Seq() != 2 {
+		t.Fatal(c.Seq())
+	}
+	if c.SeekKey([]byte("e")) || !c.SeekKey([]byte("a")) {
+		t.Fatal("SeekKey ignores bounds")
+	}
+
+	// Data of items past the bound is not read
+	var read string
+	c.SetFilter(&Filter{Data: func(data []byte) bool {
+		read += "x"
+		return true
+	}})
+	for ok := c.First(); ok; ok = c.Next() {
+	}
+	if read != "xxx" {
+		t.Fatal(read)
+	}
+}
+
+func TestCursor_keysOnly(t *testing.T) {
+	now := time.Unix(1000, 0)
+	b := NewMemBucket()
+	b.Now = func() time.Time { return now }
+
+	if _, err := b.Put([]byte("a"), []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.PutTTL([]byte("b"), []byte("2"), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Put([]byte("c"), []byte("3")); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Minute)
+
+	var s string
+	c := b.CursorOpts(CursorOptions{KeysOnly: true})
+	for ok := c.First(); ok; ok = c.Next() {
+		s += string(c.Key())
+		if c.Seq() == 2 {
+			if _, err := c.LoadData(); err != ErrExpired {
+				t.Fatal(err)
+			}
+			continue
+		}
+		data, err := c.LoadData()
+		if err != nil {
+			t.Fatal(err)
+		}
+		s += string(data)
+	}
+	if s != "a1bc3" {
+		t.Fatal(s)
+	}
+}
@@ -0,0 +1,166 @@
+package boltseq
+
+import (
+	"sync"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// DB wraps bolt.DB and provides transactional access to boltseq buckets
+// identified by paths of nested bolt bucket names.
+type DB struct {
+	*bolt.DB
+
+	// Options are applied to every bucket handed out by the DB.
+	Options Options
+
+	mu      sync.Mutex
+	changed chan struct{} // closed on commit of a read-write transaction
+	cache   *cache
+
+	snapshots int // number of open snapshots
+}
+
+// NewDB returns DB wrapping db.
+func NewDB(db *bolt.DB) *DB {
+	return &DB{DB: db}
+}
+
+// Update executes fn within read-write transaction like bolt.DB.Update,
+// additionally waking up waiters for changes, e.g. Tail, after commit.
+func (db *DB) Update(fn func(*bolt.Tx) error) error {
+	err := db.DB.Update(fn)
+	if err == nil {
+		db.notify()
+	}
+	return err
+}
+
+// changes returns channel closed on the next commit through the DB.
+func (db *DB) changes() <-chan struct{} {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.changed == nil {
+		db.changed = make(chan struct{})
+	}
+	return db.changed
+}
+
+// notify wakes up waiters for changes and clears the cache.
+func (db *DB) notify() {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.cache != nil {
+		db.cache.clear()
+	}
+	if db.changed != nil {
+		close(db.changed)
+		db.changed = nil
+	}
+}
+
+// UpdateBucket executes fn within read-write transaction, passing boltseq bucket
+// located at path. Missing buckets along the path are created. Empty path
+// denotes root of the database.
+func (db *DB) UpdateBucket(path [][]byte, fn func(*Bucket) error) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		b, err := db.createBucket(tx, path)
+		if err != nil {
+			return err
+		}
+		return fn(b)
+	})
+}
+
+// ViewBucket executes fn within read-only transaction, passing boltseq bucket
+// located at path. Returns bolt.ErrBucketNotFound if any bucket along the path
+// doesn't exist.
+func (db *DB) ViewBucket(path [][]byte, fn func(*Bucket) error) error {
+	return db.View(func(tx *bolt.Tx) error {
+		b, err := db.bucket(tx, path)
+		if err != nil {
+			return err
+		}
+		return fn(b)
+	})
+}
+
+// UpdateBuckets executes fn within a single read-write transaction, passing
+// boltseq buckets located at paths, in the same order. Missing buckets are
+// created. Use it for atomic operations across buckets.
+func (db *DB) UpdateBuckets(paths [][][]byte, fn func(bs []*Bucket) error) error {
+	return db.Update(func(tx *bolt.Tx) error {
+		bs := make([]*Bucket, len(paths))
+		for n, path := range paths {
+			b, err := db.createBucket(tx, path)
+			if err != nil {
+				return err
+			}
+			bs[n] = b
+		}
+		return fn(bs)
+	})
+}
+
+// ViewBuckets executes fn within a single read-only transaction, passing
+// boltseq buckets located at paths, in the same order. Returns
+// bolt.ErrBucketNotFound if any bucket along the paths doesn't exist.
+func (db *DB) ViewBuckets(paths [][][]byte, fn func(bs []*Bucket) error) error {
+	return db.View(func(tx *bolt.Tx) error {
+		bs := make([]*Bucket, len(paths))
+		for n, path := range paths {
+			b, err := db.bucket(tx, path)
+			if err != nil {
+				return err
+			}
+			bs[n] = b
+		}
+		return fn(bs)
+	})
+}
+
+// beginBucket starts read-write transaction and returns bucket at path within it.
+func (db *DB) beginBucket(path [][]byte) (*bolt.Tx, *Bucket, error) {
+	tx, err := db.Begin(true)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := db.createBucket(tx, path)
+	if err != nil {
+		tx.Rollback()
+		return nil, nil, err
+	}
+	return tx, b, nil
+}
+
+// bucket returns boltseq bucket at path within tx.
+func (db *DB) bucket(tx *bolt.Tx, path [][]byte) (*Bucket, error) {
+	var loc Location = tx
+	for _, name := range path {
+		bb := loc.Bucket(name)
+		if bb == nil {
+			return nil, bolt.ErrBucketNotFound
+		}
+		loc = bb
+	}
+	return db.newBucket(loc), nil
+}
+
+// createBucket returns boltseq bucket at path within tx, creating missing buckets.
+func (db *DB) createBucket(tx *bolt.Tx, path [][]byte) (*Bucket, error) {
+	var loc Location = tx
+	for _, name := range path {
+		bb, err := loc.CreateBucketIfNotExists(name)
+		if err != nil {
+			return nil, err
+		}
+		loc = bb
+	}
+	return db.newBucket(loc), nil
+}
+
+func (db *DB) newBucket(loc Location) *Bucket {
+	b := NewBucket(loc)
+	b.Options = db.Options
+	return b
+}
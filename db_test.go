@@ -0,0 +1,83 @@
+package boltseq
+
+import (
+	"os"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestDB_updateView(t *testing.T) {
+	bdb, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(bdb.Path())
+
+	db := NewDB(bdb)
+	path := [][]byte{[]byte("a"), []byte("b")}
+
+	if err := db.ViewBucket(path, func(b *Bucket) error { return nil }); err != bolt.ErrBucketNotFound {
+		t.Fatal(err)
+	}
+
+	err = db.UpdateBucket(path, func(b *Bucket) error {
+		_, err := b.Put([]byte("x"), []byte("v"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.ViewBucket(path, func(b *Bucket) error {
+		if v := b.Get([]byte("x")); string(v.Data()) != "v" {
+			t.Fatal(v)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDB_updateBuckets(t *testing.T) {
+	bdb, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(bdb.Path())
+
+	db := NewDB(bdb)
+	paths := [][][]byte{{[]byte("a")}, {[]byte("b")}}
+
+	err = db.UpdateBuckets(paths, func(bs []*Bucket) error {
+		_, err := bs[0].Put([]byte("x"), []byte("v"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Move x from a to b atomically
+	err = db.UpdateBuckets(paths, func(bs []*Bucket) error {
+		v := bs[0].Get([]byte("x"))
+		if err := bs[0].Delete([]byte("x")); err != nil {
+			return err
+		}
+		_, err := bs[1].Put([]byte("x"), v.Data())
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.ViewBuckets(paths, func(bs []*Bucket) error {
+		if bs[0].Get([]byte("x")) != nil || string(bs[1].Get([]byte("x")).Data()) != "v" {
+			t.Fatal("not moved")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,70 @@
+package boltseq
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+)
+
+var bucketNameBlob = []byte("blob")
+
+// dedupMinSize is the minimum size of data to be deduplicated.
+// Smaller data is stored inline, as references wouldn't save space.
+const dedupMinSize = sha256.Size + 1
+
+// ErrBlobNotFound is returned when deduplicated data of an item is missing.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// loadBlob returns data stored under hash.
+func (b *Bucket) loadBlob(hash []byte) ([]byte, error) {
+	bb := b.bucket(bucketNameBlob)
+	if bb == nil {
+		return nil, ErrBlobNotFound
+	}
+	v := bb.Get(hash)
+	if len(v) < 8 {
+		return nil, ErrBlobNotFound
+	}
+	return v[8:], nil
+}
+
+// retainBlob stores data under hash, or increments its reference count
+// if already stored.
+func (b *Bucket) retainBlob(hash, data []byte) error {
+	bb, err := b.createBucket(bucketNameBlob)
+	if err != nil {
+		return err
+	}
+
+	var refs uint64
+	if v := bb.Get(hash); len(v) >= 8 {
+		refs = binary.BigEndian.Uint64(v)
+	}
+
+	v := b.arena.alloc(8 + len(data))
+	binary.BigEndian.PutUint64(v, refs+1)
+	copy(v[8:], data)
+	return bb.Put(hash, v)
+}
+
+// releaseBlob decrements reference count of data stored under hash,
+// deleting it when no longer referenced.
+func (b *Bucket) releaseBlob(hash []byte) error {
+	bb := b.bucket(bucketNameBlob)
+	if bb == nil {
+		return nil
+	}
+	v := bb.Get(hash)
+	if len(v) < 8 {
+		return nil
+	}
+
+	refs := binary.BigEndian.Uint64(v)
+	if refs <= 1 {
+		return bb.Delete(hash)
+	}
+
+	nv := append(b.arena.alloc(len(v))[:0], v...)
+	binary.BigEndian.PutUint64(nv, refs-1)
+	return bb.Put(hash, nv)
+}
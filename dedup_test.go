@@ -0,0 +1,106 @@
+package boltseq
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+// blobRefs returns reference counts of stored blobs.
+func blobRefs(b *Bucket) []uint64 {
+	var refs []uint64
+	bb := b.bucket(bucketNameBlob)
+	if bb == nil {
+		return nil
+	}
+	c := bb.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		refs = append(refs, binary.BigEndian.Uint64(v))
+	}
+	return refs
+}
+
+func TestBucket_dedup(t *testing.T) {
+	b := NewMemBucket()
+	b.Dedup = true
+
+	big := bytes.Repeat([]byte("x"), 100)
+	for _, k := range []string{"a", "b", "c"} {
+		if _, err := b.Put([]byte(k), big); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := b.Put([]byte("small"), []byte("s")); err != nil {
+		t.Fatal(err)
+	}
+	if refs := blobRefs(b); len(refs) != 1 || refs[0] != 3 {
+		t.Fatal(refs)
+	}
+
+	if v := b.Get([]byte("b")); v.Seq() != 2 || !bytes.Equal(v.Data(), big) {
+		t.Fatal(v)
+	}
+	if v := b.Get([]byte("small")); string(v.Data()) != "s" {
+		t.Fatal(v)
+	}
+	if _, data, err := b.GetSeqEntry(3); err != nil || !bytes.Equal(data, big) {
+		t.Fatal(data, err)
+	}
+	c := b.Cursor()
+	if !c.First() {
+		t.Fatal(c.Err())
+	}
+	if data, err := c.Data(); err != nil || !bytes.Equal(data, big) {
+		t.Fatal(data, err)
+	}
+
+	// Sequence number is kept intact when reordering
+	if seq, err := b.Touch([]byte("a")); err != nil || seq != 5 {
+		t.Fatal(seq, err)
+	}
+	if v := b.Get([]byte("a")); v.Seq() != 5 || !bytes.Equal(v.Data(), big) {
+		t.Fatal(v)
+	}
+
+	// Overwrite and delete release references
+	if _, err := b.Put([]byte("a"), []byte("other")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Delete([]byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	if refs := blobRefs(b); len(refs) != 1 || refs[0] != 1 {
+		t.Fatal(refs)
+	}
+	if deleted, err := b.DeleteSeq(3); !deleted || err != nil {
+		t.Fatal(err)
+	}
+	if refs := blobRefs(b); len(refs) != 0 {
+		t.Fatal(refs)
+	}
+}
+
+func TestBucket_dedupMissingBlob(t *testing.T) {
+	b := NewMemBucket()
+	b.Dedup = true
+
+	if _, err := b.Put([]byte("a"), bytes.Repeat([]byte("x"), 100)); err != nil {
+		t.Fatal(err)
+	}
+	c := b.bucket(bucketNameBlob).Cursor()
+	c.First()
+	if err := c.Delete(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := b.GetValue([]byte("a")); !errors.Is(err, ErrBlobNotFound) {
+		t.Fatal(err)
+	}
+	if v := b.Get([]byte("a")); v == nil || v.IsValid() {
+		t.Fatal(v)
+	}
+	if err := b.Delete([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+}
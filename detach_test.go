@@ -0,0 +1,67 @@
+package boltseq
+
+import (
+	"os"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestBucket_detach(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := NewBucket(tx.Bucket(testBucketName)).Put([]byte("key"), []byte("data"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var v Value
+	var key, data []byte
+	var e Entry
+	err = db.View(func(tx *bolt.Tx) error {
+		b := NewBucket(tx.Bucket(testBucketName))
+		b.Detach = true
+		v = b.Get([]byte("key"))
+		c := b.Cursor()
+		c.First()
+		key = c.Key()
+		if data, err = c.Data(); err != nil {
+			return err
+		}
+
+		b.Detach = false
+		c.First()
+		ce, err := c.Entry()
+		e = ce.Clone()
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Overwrite pages the slices would point to otherwise
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := NewBucket(tx.Bucket(testBucketName)).Put([]byte("key"), []byte("xxxx"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(v.Data()) != "data" || v.Seq() != 1 || string(key) != "key" || string(data) != "data" {
+		t.Fatal(v, key, data)
+	}
+	if string(e.Key) != "key" || string(e.Data) != "data" || e.Seq != 1 {
+		t.Fatal(e)
+	}
+	if d := newValue(1, []byte("x")).DataCopy(); string(d) != "x" {
+		t.Fatal(d)
+	}
+}
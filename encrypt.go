@@ -0,0 +1,118 @@
+package boltseq
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+)
+
+// nonceSize is the size of AES-GCM nonces stored in value headers.
+const nonceSize = 12
+
+// ErrDecrypt is returned when data of an item can't be decrypted, e.g.
+// because encryption key is wrong or not set.
+var ErrDecrypt = errors.New("can't decrypt data")
+
+// encryption holds settings of at-rest encryption.
+type encryption struct {
+	aead     cipher.AEAD
+	nonceKey []byte // for deriving nonces of deduplicated data
+}
+
+// WithEncryption enables encryption of data with AES-GCM using the given
+// 16, 24 or 32-byte key. Every value gets a random nonce stored in its header
+// and is bound to its key and sequence number, so stored data can't be moved
+// to another item. Deduplicated data is shared by items, so instead its nonce
+// is derived from data and identical data encrypts identically. Keys and
+// sequence numbers are stored unencrypted; encrypting keys is not supported.
+func (o *Options) WithEncryption(key []byte) error {
+	if _, err := aes.NewCipher(key); err != nil {
+		return err
+	}
+	block, err := aes.NewCipher(subkey(key, "aes")[:len(key)])
+	if err != nil {
+		return err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+	o.enc = &encryption{aead: aead, nonceKey: subkey(key, "nonce")}
+	return nil
+}
+
+// subkey derives a key for the given purpose from the encryption key.
+func subkey(key []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// valueAD returns additional data binding encrypted data to the stored key
+// and sequence number of its item.
+func valueAD(skey []byte, seq uint64) []byte {
+	return append(seqKey(seq), skey...)
+}
+
+// encrypt encrypts data authenticated with ad, writing nonce into nonce.
+// Nonce is derived from data if ad is nil, which is used for deduplicated data.
+func (e *encryption) encrypt(nonce, data, ad []byte) ([]byte, error) {
+	if ad == nil {
+		mac := hmac.New(sha256.New, e.nonceKey)
+		mac.Write(data)
+		copy(nonce, mac.Sum(nil))
+	} else if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return e.aead.Seal(nil, nonce, data, ad), nil
+}
+
+func (e *encryption) decrypt(nonce, data, ad []byte) ([]byte, error) {
+	if e == nil {
+		return nil, ErrDecrypt
+	}
+	p, err := e.aead.Open(nil, nonce, data, ad)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return p, nil
+}
+
+// rebind returns a copy of stored value v of skey with sequence number seq
+// and, if its data is encrypted, re-encrypted for stored key to, so the item
+// can be moved to another key or sequence number.
+func (b *Bucket) rebind(v Value, skey, to []byte, seq uint64) (Value, error) {
+	nv := Value(append(b.arena.alloc(len(v))[:0], v...))
+	h, n, ok := parseHeader(nv)
+	if !ok {
+		return nil, ErrInvalidValue
+	}
+	if h.flags&flagEncrypted == 0 || h.flags&flagDedup != 0 {
+		setSeq(nv, seq)
+		return nv, nil
+	}
+
+	data, err := b.payload(h, nv[n:])
+	if err == nil {
+		data, err = b.enc.decrypt(h.nonce, data, valueAD(skey, nv.Seq()))
+	}
+	if err != nil {
+		return nil, err
+	}
+	h.nonce = make([]byte, nonceSize)
+	if data, err = b.enc.encrypt(h.nonce, data, valueAD(to, seq)); err != nil {
+		return nil, err
+	}
+	if h.flags&flagChunked != 0 {
+		if err := b.deleteChunks(nv[n:]); err != nil {
+			return nil, err
+		}
+		if data, err = b.storeChunks(data); err != nil {
+			return nil, err
+		}
+	}
+	return b.arena.newValue(seq, &h, data), nil
+}
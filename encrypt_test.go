@@ -0,0 +1,158 @@
+package boltseq
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestBucket_encryption(t *testing.T) {
+	b := NewMemBucket()
+	if err := b.WithEncryption([]byte("short")); err == nil {
+		t.Fatal("expected error for invalid key")
+	}
+	key := bytes.Repeat([]byte("k"), 32)
+	if err := b.WithEncryption(key); err != nil {
+		t.Fatal(err)
+	}
+	b.Compression = CompressAuto
+
+	secret := bytes.Repeat([]byte("secret "), 10)
+	if _, err := b.Put([]byte("a"), secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Put([]byte("b"), secret); err != nil {
+		t.Fatal(err)
+	}
+
+	raw := b.get([]byte("a"))
+	if bytes.Contains(raw, []byte("secret")) {
+		t.Fatal("data stored unencrypted")
+	}
+	if bytes.Equal(raw[8:], b.get([]byte("b"))[8:]) {
+		t.Fatal("nonce reused")
+	}
+	if v := b.Get([]byte("a")); v.Seq() != 1 || !bytes.Equal(v.Data(), secret) {
+		t.Fatal(v)
+	}
+	c := b.Cursor()
+	c.Last()
+	if data, err := c.Data(); err != nil || !bytes.Equal(data, secret) {
+		t.Fatal(data, err)
+	}
+
+	// Wrong key fails to decrypt
+	if err := b.WithEncryption(bytes.Repeat([]byte("x"), 32)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.GetValue([]byte("a")); !errors.Is(err, ErrDecrypt) {
+		t.Fatal(err)
+	}
+	b.enc = nil
+	if _, err := b.GetValue([]byte("a")); !errors.Is(err, ErrDecrypt) {
+		t.Fatal(err)
+	}
+}
+
+func TestBucket_encryptionDedup(t *testing.T) {
+	b := NewMemBucket()
+	b.Dedup = true
+	if err := b.WithEncryption(bytes.Repeat([]byte("k"), 16)); err != nil {
+		t.Fatal(err)
+	}
+
+	data := bytes.Repeat([]byte("x"), 100)
+	for _, k := range []string{"a", "b"} {
+		if _, err := b.Put([]byte(k), data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if refs := blobRefs(b); len(refs) != 1 || refs[0] != 2 {
+		t.Fatal(refs)
+	}
+	if v := b.Get([]byte("b")); !bytes.Equal(v.Data(), data) {
+		t.Fatal(v)
+	}
+}
+
+func TestBucket_encryptionBound(t *testing.T) {
+	b := NewMemBucket()
+	if err := b.WithEncryption(bytes.Repeat([]byte("k"), 16)); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"a", "b"} {
+		if _, err := b.Put([]byte(k), []byte("data of "+k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Data copied to another key or sequence number doesn't decrypt
+	bd := b.bucket(bucketNameData)
+	va := b.get([]byte("a")).Clone()
+	if err := bd.Put([]byte("b"), setSeqCopy(va, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.GetValue([]byte("b")); !errors.Is(err, ErrDecrypt) {
+		t.Fatal(err)
+	}
+	if err := bd.Put([]byte("a"), setSeqCopy(va, 3)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.GetValue([]byte("a")); !errors.Is(err, ErrDecrypt) {
+		t.Fatal(err)
+	}
+}
+
+func setSeqCopy(v Value, seq uint64) Value {
+	v = v.Clone()
+	setSeq(v, seq)
+	return v
+}
+
+func TestBucket_encryptionMoves(t *testing.T) {
+	for _, chunk := range []int{0, 8} {
+		b := NewMemBucket()
+		b.Paranoid = true
+		b.ChunkSize = chunk
+		if err := b.WithEncryption(bytes.Repeat([]byte("k"), 16)); err != nil {
+			t.Fatal(err)
+		}
+		for _, k := range []string{"a", "b", "c"} {
+			if _, err := b.Put([]byte(k), []byte("data of "+k)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := b.Rename([]byte("c"), []byte("d")); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.Swap([]byte("a"), []byte("b")); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.SwapSeq([]byte("a"), []byte("d")); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := b.Touch([]byte("b")); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.MoveBefore([]byte("b"), []byte("d")); err != nil {
+			t.Fatal(err)
+		}
+
+		for k, want := range map[string]string{"a": "data of b", "b": "data of a", "d": "data of c"} {
+			if v, err := b.GetValue([]byte(k)); err != nil || string(v.Data()) != want {
+				t.Fatal(chunk, k, v, err)
+			}
+		}
+		if s := orderOf(t, b); s != "b1 d2 a3 " {
+			t.Fatal(chunk, s)
+		}
+		if bc := b.bucket(bucketNameChunk); bc != nil {
+			n := 0
+			forEach(bc, func(k, v []byte) error { n++; return nil })
+			if n != 12 {
+				t.Fatal("chunks:", n)
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package boltseq
+
+import "errors"
+
+// ErrSeqNotFound is returned when there is no item with requested sequence number.
+var ErrSeqNotFound = errors.New("sequence number not found")
+
+// Entry is a single bucket item.
+type Entry struct {
+	Seq  uint64 `json:"seq"`
+	Key  []byte `json:"key"`
+	Data []byte `json:"data"`
+}
+
+// Clone returns copy of the entry, which stays valid after the transaction ends.
+func (e Entry) Clone() Entry {
+	return Entry{
+		Seq:  e.Seq,
+		Key:  append([]byte{}, e.Key...),
+		Data: append([]byte{}, e.Data...),
+	}
+}
+
+// Entry returns the current item. Slices are only valid for the life of the
+// transaction.
+func (c *Cursor) Entry() (Entry, error) {
+	data, err := c.Data()
+	if err != nil {
+		return Entry{}, err
+	}
+	return Entry{Seq: c.seq, Key: c.Key(), Data: data}, nil
+}
+
+// GetSeqEntry returns key and data of the item with sequence number seq.
+// Returns ErrSeqNotFound if there is no such item, ErrInvalidKey if its data
+// is missing, ErrInvalidValue if data is corrupted and ErrSeqMismatch if data
+// belongs to a different sequence number.
+func (b *Bucket) GetSeqEntry(seq uint64) (key, data []byte, err error) {
+	key, data, err = b.getSeqEntry(seq)
+	return key, data, opError("get", key, seq, err)
+}
+
+func (b *Bucket) getSeqEntry(seq uint64) (key, data []byte, err error) {
+	key = b.GetSeq(seq)
+	if key == nil {
+		return nil, nil, ErrSeqNotFound
+	}
+
+	v := b.get(key)
+	if v == nil {
+		return key, nil, ErrInvalidKey
+	}
+	vseq, ok := v.SeqOK()
+	if !ok {
+		return key, nil, ErrInvalidValue
+	}
+	if vseq != seq {
+		return key, nil, ErrSeqMismatch
+	}
+	if v, err = b.decode(b.storeKey(key), v); err != nil {
+		return key, nil, err
+	}
+	return key, v.Data(), nil
+}
+
+// First returns the item with the lowest sequence number.
+// Returns false if the bucket is empty.
+func (b *Bucket) First() (Entry, bool, error) {
+	c := b.Cursor()
+	return c.entryAt(c.First())
+}
+
+// Last returns the item with the highest sequence number, i.e. the newest one.
+// Returns false if the bucket is empty.
+func (b *Bucket) Last() (Entry, bool, error) {
+	c := b.Cursor()
+	return c.entryAt(c.Last())
+}
+
+// entryAt returns the current item if ok is true.
+func (c *Cursor) entryAt(ok bool) (Entry, bool, error) {
+	if !ok {
+		return Entry{}, false, c.Err()
+	}
+	e, err := c.Entry()
+	return e, err == nil, err
+}
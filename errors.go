@@ -0,0 +1,34 @@
+package boltseq
+
+import "fmt"
+
+// OpError describes a failed bucket operation with the key or sequence
+// number it was performed on. Seq is zero when unknown, Key is nil for
+// operations by sequence number. It matches the underlying error via errors.Is.
+type OpError struct {
+	Op  string
+	Key []byte
+	Seq uint64
+	Err error
+}
+
+func (e *OpError) Error() string {
+	if e.Key == nil {
+		return fmt.Sprintf("%s seq %d: %v", e.Op, e.Seq, e.Err)
+	}
+	return fmt.Sprintf("%s %q: %v", e.Op, e.Key, e.Err)
+}
+
+func (e *OpError) Unwrap() error {
+	return e.Err
+}
+
+// opError wraps err in OpError. Errors already carrying the context are
+// returned as is.
+func opError(op string, key []byte, seq uint64, err error) error {
+	switch err.(type) {
+	case nil, *OpError, *LimitError:
+		return err
+	}
+	return &OpError{Op: op, Key: key, Seq: seq, Err: err}
+}
@@ -0,0 +1,36 @@
+package boltseq
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOpError(t *testing.T) {
+	b := NewMemBucket()
+	if _, err := b.Put([]byte("a"), []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+
+	b.BeforePut(func(key, value []byte) ([]byte, error) { return nil, ErrInvalidValue })
+	_, err := b.Put([]byte("abc123"), nil)
+	if !errors.Is(err, ErrInvalidValue) || err.Error() != `put "abc123": invalid value` {
+		t.Fatal(err)
+	}
+	var oe *OpError
+	if !errors.As(err, &oe) || oe.Op != "put" || string(oe.Key) != "abc123" {
+		t.Fatal(oe)
+	}
+
+	_, _, err = b.GetSeqEntry(7)
+	if !errors.Is(err, ErrSeqNotFound) || err.Error() != "get seq 7: sequence number not found" {
+		t.Fatal(err)
+	}
+
+	// Limit errors carry context already
+	b = NewMemBucket()
+	b.Limits.MaxKeySize = 1
+	_, err = b.Put([]byte("ab"), nil)
+	if _, ok := err.(*LimitError); !ok {
+		t.Fatal(err)
+	}
+}
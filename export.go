@@ -0,0 +1,129 @@
+package boltseq
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// maxExportEntrySize is the size of the largest entry bbolt can store,
+// encoded by Entry.MarshalBinary.
+const maxExportEntrySize = 2*binary.MaxVarintLen64 + bolt.MaxKeySize + bolt.MaxValueSize
+
+// ExportFormat is encoding of items written by Export.
+type ExportFormat int
+
+const (
+	// ExportJSON writes entries as JSON objects, one per line.
+	ExportJSON ExportFormat = iota
+	// ExportBinary writes entries encoded by Entry.MarshalBinary, each
+	// preceded by its uvarint length.
+	ExportBinary
+)
+
+// ExportOptions configures Export and Import. The zero value selects all
+// items in JSON format.
+type ExportOptions struct {
+	Format ExportFormat
+
+	// Prefix, if set, selects items with keys having the prefix.
+	Prefix []byte
+
+	// MinSeq and MaxSeq, if non-zero, select items with sequence numbers
+	// in the range, inclusive.
+	MinSeq, MaxSeq uint64
+}
+
+func (o *ExportOptions) match(e Entry) bool {
+	return bytes.HasPrefix(e.Key, o.Prefix) && e.Seq >= o.MinSeq && (o.MaxSeq == 0 || e.Seq <= o.MaxSeq)
+}
+
+// Export writes items selected by opts to w in sequence order.
+// Returns number of items written.
+func (b *Bucket) Export(w io.Writer, opts ExportOptions) (int, error) {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	var lp [binary.MaxVarintLen64]byte
+
+	c := b.CursorOpts(CursorOptions{Min: opts.MinSeq, Max: opts.MaxSeq})
+	if opts.Prefix != nil {
+		c.SetFilter(&Filter{Prefix: opts.Prefix})
+	}
+	n := 0
+	for ok := c.First(); ok; ok = c.Next() {
+		e, err := c.Entry()
+		if err != nil {
+			return n, opError("get", c.Key(), c.Seq(), err)
+		}
+		switch opts.Format {
+		case ExportBinary:
+			p, _ := e.MarshalBinary()
+			if _, err = bw.Write(lp[:binary.PutUvarint(lp[:], uint64(len(p)))]); err == nil {
+				_, err = bw.Write(p)
+			}
+		default:
+			err = enc.Encode(e)
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+	if err := c.Err(); err != nil {
+		return n, err
+	}
+	return n, bw.Flush()
+}
+
+// Import reads items written by Export from r and puts those selected by
+// opts into the bucket, keeping their sequence numbers. Returns number of
+// items put. Returns ErrInvalidValue if the binary stream is corrupt.
+func (b *Bucket) Import(r io.Reader, opts ExportOptions) (int, error) {
+	br := bufio.NewReader(r)
+	dec := json.NewDecoder(br)
+
+	n := 0
+	for {
+		var e Entry
+		var err error
+		switch opts.Format {
+		case ExportBinary:
+			var size uint64
+			if size, err = binary.ReadUvarint(br); err != nil {
+				break
+			}
+			if size > maxExportEntrySize {
+				err = ErrInvalidValue
+				break
+			}
+			// Buffer grows as data is read, so a corrupt size can't make
+			// it allocate more than the input holds
+			var p bytes.Buffer
+			if _, err = io.CopyN(&p, br, int64(size)); err == nil {
+				err = e.UnmarshalBinary(p.Bytes())
+			} else if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+		default:
+			err = dec.Decode(&e)
+		}
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, err
+		}
+
+		if !opts.match(e) {
+			continue
+		}
+		if _, err := b.put(e.Key, e.Data, e.Seq); err != nil {
+			return n, err
+		}
+		n++
+	}
+}
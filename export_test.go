@@ -0,0 +1,77 @@
+package boltseq
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestBucket_export(t *testing.T) {
+	for _, format := range []ExportFormat{ExportJSON, ExportBinary} {
+		b := NewMemBucket()
+		for _, k := range []string{"t1/a", "t2/b", "t1/c", "t1/d", "t2/e"} {
+			if _, err := b.Put([]byte(k), []byte("v-"+k)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var buf bytes.Buffer
+		opts := ExportOptions{Format: format, Prefix: []byte("t1/"), MaxSeq: 3}
+		if n, err := b.Export(&buf, opts); err != nil || n != 2 {
+			t.Fatal(format, n, err)
+		}
+
+		dst := NewMemBucket()
+		if n, err := dst.Import(bytes.NewReader(buf.Bytes()), ExportOptions{Format: format}); err != nil || n != 2 {
+			t.Fatal(format, n, err)
+		}
+		if got := orderOf(t, dst); got != "t1/a1 t1/c3 " {
+			t.Fatal(format, got)
+		}
+		if v := dst.Get([]byte("t1/c")); string(v.Data()) != "v-t1/c" {
+			t.Fatal(format, v)
+		}
+
+		// Import filters too
+		buf.Reset()
+		if _, err := b.Export(&buf, ExportOptions{Format: format}); err != nil {
+			t.Fatal(format, err)
+		}
+		dst = NewMemBucket()
+		opts = ExportOptions{Format: format, Prefix: []byte("t2/"), MinSeq: 3}
+		if n, err := dst.Import(&buf, opts); err != nil || n != 1 {
+			t.Fatal(format, n, err)
+		}
+		if got := orderOf(t, dst); got != "t2/e5 " {
+			t.Fatal(format, got)
+		}
+	}
+}
+
+func TestBucket_importTruncated(t *testing.T) {
+	b := NewMemBucket()
+	if _, err := b.Put([]byte("a"), []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if _, err := b.Export(&buf, ExportOptions{Format: ExportBinary}); err != nil {
+		t.Fatal(err)
+	}
+
+	p := buf.Bytes()
+	if _, err := NewMemBucket().Import(bytes.NewReader(p[:len(p)-1]), ExportOptions{Format: ExportBinary}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	// Corrupt sizes fail without allocating them, as do corrupt entries
+	if _, err := NewMemBucket().Import(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f}), ExportOptions{Format: ExportBinary}); !errors.Is(err, ErrInvalidValue) {
+		t.Fatal(err)
+	}
+	if _, err := NewMemBucket().Import(bytes.NewReader([]byte{3, 0}), ExportOptions{Format: ExportBinary}); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatal(err)
+	}
+	if _, err := NewMemBucket().Import(bytes.NewReader([]byte{2, 5, 0xff}), ExportOptions{Format: ExportBinary}); !errors.Is(err, ErrInvalidValue) {
+		t.Fatal(err)
+	}
+}
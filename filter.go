@@ -0,0 +1,59 @@
+package boltseq
+
+import "bytes"
+
+// Filter selects items visited by a cursor. Nil predicates match everything.
+// Predicates are checked in order Seq, Prefix, Key, Flags, Data, so data is
+// only fetched for items matching the others.
+type Filter struct {
+	Seq   func(seq uint64) bool
+	Key   func(key []byte) bool
+	Flags func(flags byte) bool
+	Data  func(data []byte) bool
+
+	// Prefix, if set, is the required key prefix, checked before Key.
+	Prefix []byte
+}
+
+func (f *Filter) match(c *Cursor) bool {
+	if f.Seq != nil && !f.Seq(c.seq) {
+		return false
+	}
+	key := c.b.userKey(c.key)
+	if f.Prefix != nil && !bytes.HasPrefix(key, f.Prefix) {
+		return false
+	}
+	if f.Key != nil && !f.Key(key) {
+		return false
+	}
+	if f.Flags != nil && !f.Flags(c.Flags()) {
+		return false
+	}
+	if f.Data != nil {
+		data, err := c.Data()
+		if err != nil {
+			// Let the caller see the error when reading data
+			return true
+		}
+		return f.Data(data)
+	}
+	return true
+}
+
+// SetFilter makes the cursor skip items not matching f. Nil removes the filter.
+func (c *Cursor) SetFilter(f *Filter) {
+	if c.m != nil {
+		for _, sc := range c.m.cs {
+			sc.SetFilter(f)
+		}
+		return
+	}
+	c.filter = f
+}
+
+// ForEachFilter is like ForEach, but fn is only called for items matching f.
+func (b *Bucket) ForEachFilter(f *Filter, fn func(seq uint64, key, data []byte) error) error {
+	c := b.Cursor()
+	c.SetFilter(f)
+	return b.forEach(c, c.First, c.Next, fn)
+}
@@ -0,0 +1,45 @@
+package boltseq
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestCursor_filter(t *testing.T) {
+	b := NewMemBucket()
+	for n := 0; n < 10; n++ {
+		if _, err := b.Put([]byte(fmt.Sprintf("k%d", n)), []byte(fmt.Sprint(n%3))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dataCalls := 0
+	f := &Filter{
+		Seq: func(seq uint64) bool { return seq%2 == 0 },
+		Key: func(key []byte) bool { return !bytes.Equal(key, []byte("k3")) },
+		Data: func(data []byte) bool {
+			dataCalls++
+			return string(data) != "0"
+		},
+	}
+
+	var keys []string
+	err := b.ForEachFilter(f, func(seq uint64, key, data []byte) error {
+		keys = append(keys, string(key))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Even seqs are k1,k3,k5,k7,k9; k3 excluded by key, k9 by data
+	if fmt.Sprint(keys) != "[k1 k5 k7]" || dataCalls != 4 {
+		t.Fatal(keys, dataCalls)
+	}
+
+	c := b.Cursor()
+	c.SetFilter(f)
+	if !c.Last() || string(c.Key()) != "k7" || !c.Prev() || string(c.Key()) != "k5" {
+		t.Fatal(string(c.Key()))
+	}
+}
@@ -0,0 +1,61 @@
+package boltseq
+
+// Flags returns application flags of the value, see PutFlags.
+func (v Value) Flags() byte {
+	h, _, _ := parseHeader(v)
+	return h.user
+}
+
+// PutFlags is like Put, additionally storing application flags of the item,
+// e.g. to mark it pending or processed.
+func (b *Bucket) PutFlags(key, value []byte, flags byte) (uint64, error) {
+	var h header
+	h.setUser(flags)
+	return b.putHeader(key, value, 0, h)
+}
+
+// SetFlags changes application flags of the key, keeping its sequence number
+// and data intact. Returns ErrKeyNotFound if the key doesn't exist.
+func (b *Bucket) SetFlags(key []byte, flags byte) error {
+	return opError("set flags", key, 0, b.setFlags(key, flags))
+}
+
+func (b *Bucket) setFlags(key []byte, flags byte) error {
+	v := b.get(key)
+	if v == nil {
+		return ErrKeyNotFound
+	}
+	h, n, ok := parseHeader(v)
+	if !ok {
+		return ErrInvalidValue
+	}
+	if h.user == flags {
+		return nil
+	}
+
+	if err := b.logUpdate(key, key, v, v.Seq()); err != nil {
+		return err
+	}
+	h.setUser(flags)
+	nv := Value(b.arena.alloc(8 + h.size() + len(v) - n))
+	h.putValue(nv, v.Seq(), v[n:])
+	if err := b.bucket(bucketNameData).Put(b.storeKey(key), nv); err != nil {
+		return err
+	}
+	if err := b.growSize(int64(len(nv) - len(v))); err != nil {
+		return err
+	}
+	return b.verify([][]byte{key})
+}
+
+// Flags returns application flags of the current item.
+func (c *Cursor) Flags() byte {
+	if c.m != nil {
+		if cur := c.m.current(); cur != nil {
+			return cur.Flags()
+		}
+		return 0
+	}
+	v, _ := c.dp.Get(c.key)
+	return Value(v).Flags()
+}
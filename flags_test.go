@@ -0,0 +1,63 @@
+package boltseq
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+const (
+	flagPending = 1 << iota
+	flagAcked
+)
+
+func TestBucket_flags(t *testing.T) {
+	b := NewMemBucket()
+	b.Dedup = true
+	big := bytes.Repeat([]byte("x"), 100)
+
+	if _, err := b.PutFlags([]byte("a"), []byte("1"), flagPending); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Put([]byte("b"), []byte("2")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.PutFlags([]byte("c"), big, flagPending); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := b.Get([]byte("a")); v.Flags() != flagPending || string(v.Data()) != "1" {
+		t.Fatal(v)
+	}
+	if v := b.Get([]byte("c")); v.Flags() != flagPending || !bytes.Equal(v.Data(), big) {
+		t.Fatal(v)
+	}
+
+	if err := b.SetFlags([]byte("a"), flagAcked); err != nil {
+		t.Fatal(err)
+	}
+	if v := b.Get([]byte("a")); v.Seq() != 1 || v.Flags() != flagAcked || string(v.Data()) != "1" {
+		t.Fatal(v)
+	}
+	if err := b.SetFlags([]byte("x"), flagAcked); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatal(err)
+	}
+
+	c := b.Cursor()
+	c.SetFilter(&Filter{Flags: func(f byte) bool { return f&flagPending != 0 }})
+	var keys string
+	for ok := c.First(); ok; ok = c.Next() {
+		keys += string(c.Key())
+	}
+	if keys != "c" {
+		t.Fatal(keys)
+	}
+
+	// Clearing flags
+	if err := b.SetFlags([]byte("c"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if v := b.Get([]byte("c")); v.Flags() != 0 || !bytes.Equal(v.Data(), big) {
+		t.Fatal(v)
+	}
+}
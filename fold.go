@@ -0,0 +1,14 @@
+package boltseq
+
+// Fold computes an aggregate over all items of the bucket in sequence order,
+// calling fn with the accumulator returned for the previous item, starting
+// with init. Iteration stops on the first error returned by fn.
+func Fold[T any](b *Bucket, init T, fn func(acc T, seq uint64, key, data []byte) (T, error)) (T, error) {
+	acc := init
+	err := b.ForEach(func(seq uint64, key, data []byte) error {
+		var err error
+		acc, err = fn(acc, seq, key, data)
+		return err
+	})
+	return acc, err
+}
@@ -0,0 +1,35 @@
+package boltseq
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestFold(t *testing.T) {
+	b := NewMemBucket()
+	for n := 1; n <= 10; n++ {
+		if _, err := b.Put([]byte{byte(n)}, []byte(strconv.Itoa(n))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sum, err := Fold(b, 0, func(acc int, seq uint64, key, data []byte) (int, error) {
+		n, err := strconv.Atoi(string(data))
+		return acc + n, err
+	})
+	if err != nil || sum != 55 {
+		t.Fatal(sum, err)
+	}
+
+	errStop := errors.New("stop")
+	last, err := Fold(b, uint64(0), func(acc uint64, seq uint64, key, data []byte) (uint64, error) {
+		if seq > 3 {
+			return acc, errStop
+		}
+		return seq, nil
+	})
+	if err != errStop || last != 3 {
+		t.Fatal(last, err)
+	}
+}
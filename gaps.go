@@ -0,0 +1,46 @@
+package boltseq
+
+// SeqRange is an inclusive range of sequence numbers.
+type SeqRange struct {
+	Min, Max uint64
+}
+
+// Len returns number of sequence numbers in the range.
+func (r SeqRange) Len() uint64 {
+	return r.Max - r.Min + 1
+}
+
+// MinSeq returns the lowest sequence number in the bucket, or 0 if empty.
+func (b *Bucket) MinSeq() (uint64, error) {
+	c := b.Cursor()
+	if !c.First() {
+		return 0, c.Err()
+	}
+	return c.Seq(), nil
+}
+
+// MaxSeq returns the highest sequence number in the bucket, or 0 if empty.
+func (b *Bucket) MaxSeq() (uint64, error) {
+	c := b.Cursor()
+	if !c.Last() {
+		return 0, c.Err()
+	}
+	return c.Seq(), nil
+}
+
+// Gaps returns ranges of sequence numbers missing between MinSeq and MaxSeq,
+// i.e. belonging to items deleted or overwritten since. Only the sequence
+// sub-bucket is read.
+func (b *Bucket) Gaps() ([]SeqRange, error) {
+	var gaps []SeqRange
+	var last uint64
+
+	c := b.rawCursor()
+	for ok := c.First(); ok; ok = c.Next() {
+		if last != 0 && c.Seq() > last+1 {
+			gaps = append(gaps, SeqRange{Min: last + 1, Max: c.Seq() - 1})
+		}
+		last = c.Seq()
+	}
+	return gaps, c.Err()
+}
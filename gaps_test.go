@@ -0,0 +1,39 @@
+package boltseq
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBucket_gaps(t *testing.T) {
+	b := NewMemBucket()
+	if gaps, err := b.Gaps(); err != nil || gaps != nil {
+		t.Fatal(gaps, err)
+	}
+
+	for _, k := range []string{"a", "b", "c", "d", "e", "f", "g"} {
+		if _, err := b.Put([]byte(k), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, k := range []string{"a", "c", "d", "g"} {
+		if err := b.Delete([]byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := b.Put([]byte("b"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Left with e5 f6 b8
+	if min, err := b.MinSeq(); err != nil || min != 5 {
+		t.Fatal(min, err)
+	}
+	if max, err := b.MaxSeq(); err != nil || max != 8 {
+		t.Fatal(max, err)
+	}
+	gaps, err := b.Gaps()
+	if err != nil || fmt.Sprint(gaps) != "[{7 7}]" || gaps[0].Len() != 1 {
+		t.Fatal(gaps, err)
+	}
+}
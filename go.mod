@@ -1,6 +1,6 @@
 module github.com/tg/boltseq
 
-go 1.13
+go 1.18
 
 require (
 	go.etcd.io/bbolt v1.3.3
@@ -0,0 +1,90 @@
+package boltseq
+
+import (
+	"crypto/sha256"
+	"errors"
+)
+
+// ErrReservedKey is returned for keys in the form of hashed keys, which can't
+// be stored while HashKeys is set.
+var ErrReservedKey = errors.New("key reserved for hashed keys")
+
+// bucketNameKeys is the sub-bucket mapping hashed keys to original keys.
+var bucketNameKeys = []byte("keys")
+
+// Hashed keys are a marker byte followed by SHA-256 hash of the original key.
+const (
+	hashedKeyMark = 0xff
+	hashedKeySize = 1 + sha256.Size
+)
+
+// hashKeysOver returns length above which keys are hashed, or 0 if disabled.
+// It's never below hashedKeySize, so hashed keys are never hashed again.
+func (b *Bucket) hashKeysOver() int {
+	switch {
+	case b.HashKeys <= 0:
+		return 0
+	case b.HashKeys < hashedKeySize:
+		return hashedKeySize
+	}
+	return b.HashKeys
+}
+
+// storeKey returns key under which the key is stored in the data sub-bucket.
+// Keys already in stored form are returned as is.
+func (b *Bucket) storeKey(key []byte) []byte {
+	if n := b.hashKeysOver(); n == 0 || len(key) <= n {
+		return key
+	}
+	sum := sha256.Sum256(key)
+	return append([]byte{hashedKeyMark}, sum[:]...)
+}
+
+// hashed tells whether stored key is a hash of the original key.
+func (b *Bucket) hashed(stored []byte) bool {
+	return b.hashKeysOver() > 0 && len(stored) == hashedKeySize && stored[0] == hashedKeyMark
+}
+
+// checkKey returns ErrReservedKey if key would be taken for a hashed key.
+func (b *Bucket) checkKey(key []byte) error {
+	if b.hashed(key) {
+		return ErrReservedKey
+	}
+	return nil
+}
+
+// userKey returns original key for a key stored in the data sub-bucket.
+func (b *Bucket) userKey(stored []byte) []byte {
+	if !b.hashed(stored) {
+		return stored
+	}
+	if bk := b.bucket(bucketNameKeys); bk != nil {
+		if k := bk.Get(stored); k != nil {
+			return k
+		}
+	}
+	return stored
+}
+
+// putKey records original key of a hashed stored key.
+func (b *Bucket) putKey(stored, key []byte) error {
+	if len(stored) == len(key) {
+		return nil
+	}
+	bk, err := b.createBucket(bucketNameKeys)
+	if err != nil {
+		return err
+	}
+	return bk.Put(stored, key)
+}
+
+// deleteKey drops original key of a stored key, if it's hashed.
+func (b *Bucket) deleteKey(stored []byte) error {
+	if len(stored) != hashedKeySize {
+		return nil
+	}
+	if bk := b.bucket(bucketNameKeys); bk != nil {
+		return bk.Delete(stored)
+	}
+	return nil
+}
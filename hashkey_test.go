@@ -0,0 +1,115 @@
+package boltseq
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestBucket_hashKeys(t *testing.T) {
+	b := NewMemBucket()
+	b.HashKeys = 40
+	b.Paranoid = true
+
+	long := bytes.Repeat([]byte("k"), 100)
+	long2 := bytes.Repeat([]byte("l"), 100)
+	if _, err := b.Put(long, []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Put([]byte("short"), []byte("2")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Long key is stored hashed, original is kept aside
+	if v := b.bucket(bucketNameData).Get(long); v != nil {
+		t.Fatal("long key stored as is")
+	}
+	if k := b.bucket(bucketNameKeys).Get(b.storeKey(long)); !bytes.Equal(k, long) {
+		t.Fatal(k)
+	}
+	if v := b.Get(long); string(v.Data()) != "1" {
+		t.Fatal(v)
+	}
+	if k := b.GetSeq(1); !bytes.Equal(k, long) {
+		t.Fatal(k)
+	}
+	if got := orderOf(t, b); got != string(long)+"1 short2 " {
+		t.Fatal(got)
+	}
+	keys, err := b.Keys(0)
+	if err != nil || len(keys) != 2 || !bytes.Equal(keys[1], long) {
+		t.Fatal(keys, err)
+	}
+
+	c := b.CursorOpts(CursorOptions{})
+	c.SetFilter(&Filter{Prefix: []byte("kkk")})
+	if !c.First() || !bytes.Equal(c.Key(), long) || c.Next() {
+		t.Fatal(c.Key())
+	}
+	if c := b.Cursor(); !c.SeekKey(long) || c.Seq() != 1 {
+		t.Fatal(c.Seq(), c.Err())
+	}
+	for prefix, want := range map[string]int{"kkk": 1, "": 2, "s": 1, "x": 0} {
+		entries, err := b.PrefixAfter([]byte(prefix), 0, 0)
+		if err != nil || len(entries) != want {
+			t.Fatal(prefix, entries, err)
+		}
+	}
+	if e, err := b.PrefixAfter([]byte("kkk"), 0, 0); err != nil || !bytes.Equal(e[0].Key, long) || string(e[0].Data) != "1" {
+		t.Fatal(e, err)
+	}
+
+	// Modifications keep the original key in sync
+	if err := b.Rename(long, long2); err != nil {
+		t.Fatal(err)
+	}
+	if b.Get(long) != nil || string(b.Get(long2).Data()) != "1" {
+		t.Fatal(b.Get(long2))
+	}
+	if err := b.Swap(long2, []byte("short")); err != nil {
+		t.Fatal(err)
+	}
+	if v := b.Get(long2); string(v.Data()) != "2" {
+		t.Fatal(v)
+	}
+	if err := b.Delete(long2); err != nil {
+		t.Fatal(err)
+	}
+	if k, _ := b.bucket(bucketNameKeys).Cursor().First(); k != nil {
+		t.Fatal(k)
+	}
+	if got := orderOf(t, b); got != "short2 " {
+		t.Fatal(got)
+	}
+}
+
+func TestBucket_hashKeysReserved(t *testing.T) {
+	b := NewMemBucket()
+	b.HashKeys = 40
+
+	long := bytes.Repeat([]byte("k"), 100)
+	if _, err := b.Put(long, []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Put([]byte("a"), []byte("2")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Keys in the form of hashed keys can't alias them
+	fake := b.storeKey(long)
+	if _, err := b.Put(fake, []byte("x")); !errors.Is(err, ErrReservedKey) {
+		t.Fatal(err)
+	}
+	if err := b.Rename([]byte("a"), fake); !errors.Is(err, ErrReservedKey) {
+		t.Fatal(err)
+	}
+	if v := b.Get(long); string(v.Data()) != "1" {
+		t.Fatal(v)
+	}
+
+	// They're fine without hashing
+	b.HashKeys = 0
+	if _, err := b.Put(fake, []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,335 @@
+package boltseq
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// Values come in two formats. Legacy values consist of 8-byte big-endian
+// sequence number followed by data. Values with extended header have the
+// highest bit of the sequence number set, followed by format version,
+// flags byte and optional fields selected by flags, then data:
+//
+//	seq|1<<63 (8) | version (1) | flags (1) | fields... | data
+//
+// Sequence numbers are therefore limited to 63 bits.
+const (
+	seqExtBit     = 1 << 63
+	headerVersion = 1
+)
+
+// header flags
+const (
+	// data is a hash of a blob stored in the blob sub-bucket
+	flagDedup byte = 1 << iota
+	// data is id and length of data split in the chunk sub-bucket
+	flagChunked
+	// data is gzip-compressed
+	flagCompressed
+	// data is encrypted, header holds nonce
+	flagEncrypted
+	// header holds expiry time
+	flagExpiry
+	// header holds user flags
+	flagUser
+	// header holds length-prefixed origin metadata
+	flagOrigin
+)
+
+// flagsEncoded are flags meaning data needs decoding before use.
+const flagsEncoded = flagDedup | flagChunked | flagCompressed | flagEncrypted
+
+// header holds fields of an extended value header.
+// Fields follow flags in order: nonce, expiry, user flags, origin.
+type header struct {
+	flags   byte
+	nonce   []byte // set if flagEncrypted
+	expires int64  // Unix time in nanoseconds, set if flagExpiry
+	user    byte   // set if flagUser
+	origin  []byte // set if flagOrigin
+	ext     bool   // use extended header even if no flags are set
+}
+
+// size returns number of header bytes following the sequence number.
+func (h *header) size() int {
+	if h.flags == 0 && !h.ext {
+		return 0
+	}
+	n := 2
+	if h.flags&flagEncrypted != 0 {
+		n += nonceSize
+	}
+	if h.flags&flagExpiry != 0 {
+		n += 8
+	}
+	if h.flags&flagUser != 0 {
+		n++
+	}
+	if h.flags&flagOrigin != 0 {
+		n += 1 + len(h.origin)
+	}
+	return n
+}
+
+// put writes header following the sequence number into p.
+func (h *header) put(p []byte) {
+	if h.flags == 0 && !h.ext {
+		return
+	}
+	p[0] = headerVersion
+	p[1] = h.flags
+	p = p[2:]
+	if h.flags&flagEncrypted != 0 {
+		p = p[copy(p, h.nonce):]
+	}
+	if h.flags&flagExpiry != 0 {
+		binary.BigEndian.PutUint64(p, uint64(h.expires))
+		p = p[8:]
+	}
+	if h.flags&flagUser != 0 {
+		p[0] = h.user
+		p = p[1:]
+	}
+	if h.flags&flagOrigin != 0 {
+		p[0] = byte(len(h.origin))
+		copy(p[1:], h.origin)
+	}
+}
+
+// putValue writes value with the header into v, which must be of size
+// 8+h.size()+len(data).
+func (h *header) putValue(v Value, seq uint64, data []byte) {
+	hs := h.size()
+	if hs > 0 {
+		seq |= seqExtBit
+	}
+	binary.BigEndian.PutUint64(v[:8], seq)
+	h.put(v[8:])
+	copy(v[8+hs:], data)
+}
+
+// setUser sets user flags.
+func (h *header) setUser(flags byte) {
+	h.user = flags
+	if flags != 0 {
+		h.flags |= flagUser
+	} else {
+		h.flags &^= flagUser
+	}
+}
+
+// expired tells whether value with the header is expired at time now.
+func (h *header) expired(now int64) bool {
+	return h.flags&flagExpiry != 0 && h.expires <= now
+}
+
+// parseHeader parses value header. Returns offset of data within v,
+// or false if value is invalid.
+func parseHeader(v Value) (h header, offset int, ok bool) {
+	if len(v) < 8 {
+		return h, 0, false
+	}
+	if v[0]&0x80 == 0 {
+		return h, 8, true
+	}
+	if len(v) < 10 || v[8] != headerVersion {
+		return h, 0, false
+	}
+	h.flags, h.ext = v[9], true
+	offset = 10
+	if h.flags&flagEncrypted != 0 {
+		if len(v) < offset+nonceSize {
+			return h, 0, false
+		}
+		h.nonce = v[offset : offset+nonceSize]
+		offset += nonceSize
+	}
+	if h.flags&flagExpiry != 0 {
+		if len(v) < offset+8 {
+			return h, 0, false
+		}
+		h.expires = int64(binary.BigEndian.Uint64(v[offset:]))
+		offset += 8
+	}
+	if h.flags&flagUser != 0 {
+		if len(v) < offset+1 {
+			return h, 0, false
+		}
+		h.user = v[offset]
+		offset++
+	}
+	if h.flags&flagOrigin != 0 {
+		if len(v) < offset+1 || len(v) < offset+1+int(v[offset]) {
+			return h, 0, false
+		}
+		n := int(v[offset])
+		h.origin = v[offset+1 : offset+1+n]
+		offset += 1 + n
+	}
+	return h, offset, true
+}
+
+// seqKey returns key of the seq sub-bucket for sequence number seq.
+func seqKey(seq uint64) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, seq)
+	return k
+}
+
+// setSeq sets sequence number of value v, keeping its header marker.
+func setSeq(v Value, seq uint64) {
+	seq |= uint64(v[0]&0x80) << 56
+	binary.BigEndian.PutUint64(v[:8], seq)
+}
+
+// parseSeqKey parses key of the seq sub-bucket.
+func parseSeqKey(k []byte) (uint64, bool) {
+	if len(k) != 8 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint64(k), true
+}
+
+// encoded is data encoded for storing, with side effects applied by commit.
+type encoded struct {
+	h      header
+	data   []byte
+	blob   []byte // data to store under hash in data, if deduplicated
+	chunks []byte // data to split into chunks, if chunked
+	b      *Bucket
+}
+
+// encode prepares value with header h for storing under stored key skey and
+// sequence number seq according to bucket options. Data is compressed first,
+// then encrypted. Values large enough to be chunked are not deduplicated.
+func (b *Bucket) encode(skey []byte, seq uint64, value []byte, h header) (*encoded, error) {
+	e := &encoded{h: h, b: b}
+	switch ver := b.Version(); {
+	case ver > CurrentVersion:
+		return nil, ErrUnsupportedVersion
+	case ver >= Version1:
+		e.h.ext = true
+	}
+
+	z, err := compress(b.Compression, value)
+	if err != nil {
+		return nil, err
+	}
+	if z != nil {
+		e.h.flags |= flagCompressed
+		value = z
+	}
+
+	n := len(value)
+	if b.enc != nil {
+		n += b.enc.aead.Overhead()
+	}
+	switch {
+	case b.chunkSize() > 0 && n > b.chunkSize():
+		e.h.flags |= flagChunked
+	case b.Dedup && n >= dedupMinSize:
+		e.h.flags |= flagDedup
+	}
+
+	if b.enc != nil {
+		e.h.flags |= flagEncrypted
+		e.h.nonce = make([]byte, nonceSize)
+		if value, err = b.enc.encrypt(e.h.nonce, value, e.h.ad(skey, seq)); err != nil {
+			return nil, err
+		}
+	}
+
+	e.data = value
+	switch {
+	case e.h.flags&flagChunked != 0:
+		e.data, e.chunks = make([]byte, chunkRefSize), value
+	case e.h.flags&flagDedup != 0:
+		sum := sha256.Sum256(value)
+		e.data, e.blob = sum[:], value
+	}
+	return e, nil
+}
+
+// ad returns additional data authenticating encrypted data of an item with
+// stored key skey and sequence number seq. Deduplicated data is shared by
+// items, so it's not bound to any.
+func (h *header) ad(skey []byte, seq uint64) []byte {
+	if h.flags&flagDedup != 0 {
+		return nil
+	}
+	return valueAD(skey, seq)
+}
+
+// size returns number of bytes taken by the encoded value, including chunks.
+func (e *encoded) size() int64 {
+	return int64(8 + e.h.size() + len(e.data) + len(e.chunks))
+}
+
+// commit stores blobs and chunks referenced by the encoded value.
+func (e *encoded) commit() error {
+	if e.blob != nil {
+		return e.b.retainBlob(e.data, e.blob)
+	}
+	if e.chunks != nil {
+		ref, err := e.b.storeChunks(e.chunks)
+		if err != nil {
+			return err
+		}
+		copy(e.data, ref)
+	}
+	return nil
+}
+
+// decode returns value v stored under skey with plain data, loading it if needed.
+func (b *Bucket) decode(skey []byte, v Value) (Value, error) {
+	h, n, ok := parseHeader(v)
+	if !ok {
+		return nil, ErrInvalidValue
+	}
+	if h.flags&flagsEncoded == 0 {
+		return v, nil
+	}
+
+	data, err := b.payload(h, v[n:])
+	if err == nil && h.flags&flagEncrypted != 0 {
+		data, err = b.enc.decrypt(h.nonce, data, h.ad(skey, v.Seq()))
+	}
+	if err == nil && h.flags&flagCompressed != 0 {
+		data, err = decompress(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep other header fields, e.g. user flags
+	h.flags &^= flagsEncoded
+	dv := make(Value, 8+h.size()+len(data))
+	h.putValue(dv, v.Seq(), data)
+	return dv, nil
+}
+
+// payload returns data stored with header h, loading it if deduplicated or chunked.
+func (b *Bucket) payload(h header, data []byte) ([]byte, error) {
+	switch {
+	case h.flags&flagDedup != 0:
+		return b.loadBlob(data)
+	case h.flags&flagChunked != 0:
+		return b.loadChunks(data)
+	}
+	return data, nil
+}
+
+// release drops references held by stored value v, which is about to be
+// overwritten or deleted.
+func (b *Bucket) release(v Value) error {
+	h, n, ok := parseHeader(v)
+	switch {
+	case !ok:
+		return nil
+	case h.flags&flagDedup != 0:
+		return b.releaseBlob(v[n:])
+	case h.flags&flagChunked != 0:
+		return b.deleteChunks(v[n:])
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package boltseq
+
+import (
+	"bytes"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// HealthError describes a failed check of the bucket at Path.
+type HealthError struct {
+	Path [][]byte
+	Err  error
+}
+
+func (e *HealthError) Error() string {
+	return fmt.Sprintf("healthcheck %q: %v", bytes.Join(e.Path, []byte("/")), e.Err)
+}
+
+func (e *HealthError) Unwrap() error {
+	return e.Err
+}
+
+// Healthcheck quickly verifies, within a single read-only transaction, that
+// boltseq buckets at paths exist and have a supported format, that their
+// sub-buckets hold the same number of items, and that the items with the
+// lowest and highest sequence numbers are consistent. It doesn't iterate
+// items, so it's cheap enough for readiness probes. Returns HealthError for
+// the first failed bucket.
+func Healthcheck(db *DB, paths ...[][]byte) error {
+	return db.View(func(tx *bolt.Tx) error {
+		for _, path := range paths {
+			b, err := db.bucket(tx, path)
+			if err == nil {
+				err = b.healthcheck()
+			}
+			if err != nil {
+				return &HealthError{Path: path, Err: err}
+			}
+		}
+		return nil
+	})
+}
+
+func (b *Bucket) healthcheck() error {
+	if b.Version() > CurrentVersion {
+		return ErrUnsupportedVersion
+	}
+	bd, bs := b.bucket(bucketNameData), b.bucket(bucketNameSeq)
+	if (bd == nil) != (bs == nil) {
+		return ErrInvalidBucket
+	}
+	if bd == nil {
+		return nil
+	}
+	if keyCount(bd) != keyCount(bs) {
+		return ErrSeqMismatch
+	}
+
+	c := b.rawCursor()
+	for _, move := range []func() bool{c.First, c.Last} {
+		if !move() {
+			break
+		}
+		if err := c.verify(); err != nil {
+			return &CorruptionError{Seq: c.Seq(), Key: c.Key(), Err: err}
+		}
+	}
+	return c.Err()
+}
@@ -0,0 +1,63 @@
+package boltseq
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestHealthcheck(t *testing.T) {
+	bdb, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(bdb.Path())
+
+	db := NewDB(bdb)
+	good, bad := [][]byte{[]byte("good")}, [][]byte{[]byte("bad")}
+	err = db.UpdateBuckets([][][]byte{good, bad}, func(bs []*Bucket) error {
+		for _, b := range bs {
+			for _, k := range []string{"a", "b", "c"} {
+				if _, err := b.Put([]byte(k), []byte(k)); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Healthcheck(db, good, bad, [][]byte{testBucketName}); err != nil {
+		t.Fatal(err)
+	}
+	var he *HealthError
+	if err := Healthcheck(db, good, [][]byte{[]byte("missing")}); !errors.As(err, &he) || string(he.Path[0]) != "missing" || !errors.Is(err, bolt.ErrBucketNotFound) {
+		t.Fatal(err)
+	}
+
+	// Last item pointing to a different sequence number
+	err = db.Update(func(tx *bolt.Tx) error {
+		return DataBucket(tx.Bucket(bad[0])).Put([]byte("c"), newValue(9, []byte("c")))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Healthcheck(db, good, bad); !errors.As(err, &he) || string(he.Path[0]) != "bad" || !errors.Is(err, ErrSeqMismatch) {
+		t.Fatal(err)
+	}
+
+	// Missing data entry
+	err = db.Update(func(tx *bolt.Tx) error {
+		return DataBucket(tx.Bucket(good[0])).Delete([]byte("b"))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Healthcheck(db, good); !errors.Is(err, ErrSeqMismatch) {
+		t.Fatal(err)
+	}
+}
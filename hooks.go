@@ -0,0 +1,86 @@
+package boltseq
+
+// BeforePutFunc is called before a key is stored. It may return a transformed
+// value to be stored instead, or an error to abort Put.
+type BeforePutFunc func(key, value []byte) ([]byte, error)
+
+// AfterPutFunc is called after a key has been stored with sequence number seq.
+type AfterPutFunc func(seq uint64, key, value []byte) error
+
+// DeleteFunc is called before or after an existing key is deleted.
+type DeleteFunc func(key []byte) error
+
+type hooks struct {
+	beforePut    []BeforePutFunc
+	afterPut     []AfterPutFunc
+	beforeDelete []DeleteFunc
+	afterDelete  []DeleteFunc
+}
+
+// BeforePut registers fn to be called on every Put before anything is written.
+// Hooks are called in order of registration, each receiving value returned by
+// the previous one.
+//
+// Hooks only run when data is put or deleted. Operations changing keys,
+// sequence numbers or flags of existing items, i.e. Rename, Touch, MoveBefore,
+// MoveAfter, Swap, SwapSeq and SetFlags, don't run any of them.
+func (o *Options) BeforePut(fn BeforePutFunc) {
+	o.hooks.beforePut = append(o.hooks.beforePut[:len(o.hooks.beforePut):len(o.hooks.beforePut)], fn)
+}
+
+// AfterPut registers fn to be called after every successful Put.
+// See BeforePut for operations not running hooks.
+// An error returned by fn is returned from Put; the caller should roll back
+// the transaction if the write must not persist.
+func (o *Options) AfterPut(fn AfterPutFunc) {
+	o.hooks.afterPut = append(o.hooks.afterPut[:len(o.hooks.afterPut):len(o.hooks.afterPut)], fn)
+}
+
+// BeforeDelete registers fn to be called before an existing key is deleted.
+// Returning an error aborts the deletion. See BeforePut for operations not
+// running hooks.
+func (o *Options) BeforeDelete(fn DeleteFunc) {
+	o.hooks.beforeDelete = append(o.hooks.beforeDelete[:len(o.hooks.beforeDelete):len(o.hooks.beforeDelete)], fn)
+}
+
+// AfterDelete registers fn to be called after an existing key has been deleted.
+// See BeforePut for operations not running hooks.
+func (o *Options) AfterDelete(fn DeleteFunc) {
+	o.hooks.afterDelete = append(o.hooks.afterDelete[:len(o.hooks.afterDelete):len(o.hooks.afterDelete)], fn)
+}
+
+func (o *Options) runBeforePut(key, value []byte) ([]byte, error) {
+	for _, fn := range o.hooks.beforePut {
+		var err error
+		if value, err = fn(key, value); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+func (o *Options) runAfterPut(seq uint64, key, value []byte) error {
+	for _, fn := range o.hooks.afterPut {
+		if err := fn(seq, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *Options) runBeforeDelete(key []byte) error {
+	return runDeleteHooks(o.hooks.beforeDelete, key)
+}
+
+func (o *Options) runAfterDelete(key []byte) error {
+	return runDeleteHooks(o.hooks.afterDelete, key)
+}
+
+func runDeleteHooks(fns []DeleteFunc, key []byte) error {
+	for _, fn := range fns {
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package boltseq
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestBucket_hooks(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	errReadOnly := errors.New("read-only key")
+	var log []string
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		b := NewBucket(tx.Bucket(testBucketName))
+		b.BeforePut(func(key, value []byte) ([]byte, error) {
+			if string(key) == "ro" {
+				return nil, errReadOnly
+			}
+			return bytes.ToUpper(value), nil
+		})
+		b.AfterPut(func(seq uint64, key, value []byte) error {
+			log = append(log, "put "+string(key)+"="+string(value))
+			return nil
+		})
+		b.BeforeDelete(func(key []byte) error {
+			log = append(log, "before delete "+string(key))
+			return nil
+		})
+		b.AfterDelete(func(key []byte) error {
+			log = append(log, "delete "+string(key))
+			return nil
+		})
+
+		if _, err := b.Put([]byte("ro"), []byte("v")); !errors.Is(err, errReadOnly) {
+			t.Fatal(err)
+		}
+		if b.Get([]byte("ro")) != nil {
+			t.Fatal("hook didn't prevent write")
+		}
+
+		seq, err := b.Put([]byte("x"), []byte("v"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := b.Get([]byte("x")).Data(); string(d) != "V" {
+			t.Fatal(string(d))
+		}
+
+		// Missing keys don't trigger delete hooks
+		if err := b.Delete([]byte("nx")); err != nil {
+			t.Fatal(err)
+		}
+		if deleted, err := b.DeleteSeq(seq); !deleted {
+			t.Fatal(err)
+		}
+		if deleted, err := b.DeleteSeq(seq); deleted || err != nil {
+			t.Fatal(deleted, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []string{"put x=V", "before delete x", "delete x"}
+	if len(log) != len(exp) {
+		t.Fatal(log)
+	}
+	for n := range exp {
+		if log[n] != exp[n] {
+			t.Fatal(log)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package boltseq
+
+import (
+	"errors"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrNestingUnsupported is returned when a KVBucket can't hold nested buckets.
+// Buckets supporting nesting implement Store.
+var ErrNestingUnsupported = errors.New("nested buckets not supported")
+
+// Store is a storage backend holding named key-value buckets. Bucket and Cursor
+// operate on top of it, so adapters for embedded stores other than bbolt can
+// provide the same sequenced-bucket semantics. Stores are expected to be used
+// within a single transaction of the underlying database.
+type Store interface {
+	// Bucket returns bucket with the given name, or nil if it doesn't exist.
+	Bucket(name []byte) KVBucket
+	CreateBucketIfNotExists(name []byte) (KVBucket, error)
+}
+
+// KVBucket is a key-value bucket ordered by keys, with a sequence counter.
+// Slices returned by Get and cursors need to stay valid for the duration of
+// the transaction only.
+type KVBucket interface {
+	Get(key []byte) []byte
+	Put(key, value []byte) error
+	Delete(key []byte) error
+	NextSequence() (uint64, error)
+	Sequence() uint64
+	SetSequence(seq uint64) error
+	Cursor() KVCursor
+}
+
+// KVCursor iterates over a KVBucket in key order.
+// Methods return nil key when there is no item. Delete removes the current
+// item; callers must seek again afterwards, as bbolt cursors may skip the
+// item following a deleted one.
+type KVCursor interface {
+	First() (key, value []byte)
+	Last() (key, value []byte)
+	Next() (key, value []byte)
+	Prev() (key, value []byte)
+	Seek(seek []byte) (key, value []byte)
+	Delete() error
+}
+
+// forEach calls fn for every key-value pair in kb in key order.
+func forEach(kb KVBucket, fn func(k, v []byte) error) error {
+	c := kb.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setFillPercent sets fill percent for bbolt buckets, other buckets are left intact.
+func setFillPercent(kb KVBucket, fill float64) {
+	if bb, ok := kb.(boltBucket); ok {
+		bb.FillPercent = fill
+	}
+}
+
+// keyCount returns number of keys in kb, taken from page statistics for bbolt
+// buckets, which only account for committed data.
+func keyCount(kb KVBucket) int {
+	switch b := kb.(type) {
+	case boltBucket:
+		return b.Stats().KeyN
+	case *memBucket:
+		return len(b.items)
+	}
+	n := 0
+	forEach(kb, func(k, v []byte) error {
+		n++
+		return nil
+	})
+	return n
+}
+
+// BoltStore returns Store backed by bolt.Tx or bolt.Bucket.
+// This is the default used by NewBucket.
+func BoltStore(loc Location) Store {
+	return boltStore{loc}
+}
+
+type boltStore struct {
+	loc Location
+}
+
+func (s boltStore) Bucket(name []byte) KVBucket {
+	bb := s.loc.Bucket(name)
+	if bb == nil {
+		return nil
+	}
+	return boltBucket{bb}
+}
+
+func (s boltStore) CreateBucketIfNotExists(name []byte) (KVBucket, error) {
+	bb, err := s.loc.CreateBucketIfNotExists(name)
+	if err != nil {
+		return nil, err
+	}
+	return boltBucket{bb}, nil
+}
+
+// boltBucket adapts bolt.Bucket to KVBucket.
+type boltBucket struct {
+	*bolt.Bucket
+}
+
+func (b boltBucket) Cursor() KVCursor {
+	return b.Bucket.Cursor()
+}
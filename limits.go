@@ -0,0 +1,61 @@
+package boltseq
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrKeyTooLarge    = errors.New("key too large")
+	ErrValueTooLarge  = errors.New("value too large")
+	ErrInvalidKeyByte = errors.New("invalid key byte")
+)
+
+// Limits constrain keys and values accepted by Put. Zero values mean no limit.
+type Limits struct {
+	MaxKeySize   int
+	MaxValueSize int
+
+	// KeyByte, if set, tells whether byte c is allowed in keys.
+	KeyByte func(c byte) bool
+}
+
+// LimitError is returned by Put when a key or value violates bucket limits.
+// It matches ErrKeyTooLarge, ErrValueTooLarge or ErrInvalidKeyByte via errors.Is.
+type LimitError struct {
+	Key []byte
+	Err error
+
+	// Size is the offending size, or position of the invalid key byte.
+	Size int
+	// Max is the violated limit, unused for ErrInvalidKeyByte.
+	Max int
+}
+
+func (e *LimitError) Error() string {
+	if e.Err == ErrInvalidKeyByte {
+		return fmt.Sprintf("put %q: %v at %d", e.Key, e.Err, e.Size)
+	}
+	return fmt.Sprintf("put %q: %v (%d > %d)", e.Key, e.Err, e.Size, e.Max)
+}
+
+func (e *LimitError) Unwrap() error {
+	return e.Err
+}
+
+func (l *Limits) check(key, value []byte) error {
+	if l.MaxKeySize > 0 && len(key) > l.MaxKeySize {
+		return &LimitError{Key: key, Err: ErrKeyTooLarge, Size: len(key), Max: l.MaxKeySize}
+	}
+	if l.MaxValueSize > 0 && len(value) > l.MaxValueSize {
+		return &LimitError{Key: key, Err: ErrValueTooLarge, Size: len(value), Max: l.MaxValueSize}
+	}
+	if l.KeyByte != nil {
+		for n, c := range key {
+			if !l.KeyByte(c) {
+				return &LimitError{Key: key, Err: ErrInvalidKeyByte, Size: n}
+			}
+		}
+	}
+	return nil
+}
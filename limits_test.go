@@ -0,0 +1,54 @@
+package boltseq
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestBucket_limits(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		b := NewBucket(tx.Bucket(testBucketName))
+		b.Limits = Limits{
+			MaxKeySize:   4,
+			MaxValueSize: 2,
+			KeyByte:      func(c byte) bool { return c >= 'a' && c <= 'z' },
+		}
+
+		tests := []struct {
+			key, value string
+			err        error
+		}{
+			{"abcd", "vv", nil},
+			{"abcde", "v", ErrKeyTooLarge},
+			{"a", "vvv", ErrValueTooLarge},
+			{"aB", "v", ErrInvalidKeyByte},
+		}
+		for _, tt := range tests {
+			_, err := b.Put([]byte(tt.key), []byte(tt.value))
+			if !errors.Is(err, tt.err) {
+				t.Fatal(tt.key, err)
+			}
+			var le *LimitError
+			if tt.err != nil && (!errors.As(err, &le) || string(le.Key) != tt.key) {
+				t.Fatal(tt.key, err)
+			}
+		}
+
+		if b.Get([]byte("aB")) != nil {
+			t.Fatal("invalid key stored")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
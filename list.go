@@ -0,0 +1,84 @@
+package boltseq
+
+import "bytes"
+
+// Keys returns copies of up to limit keys in key order, or all keys if limit
+// is not positive. Expired items are skipped.
+func (b *Bucket) Keys(limit int) ([][]byte, error) {
+	bd := b.bucket(bucketNameData)
+	if bd == nil {
+		return nil, nil
+	}
+
+	var keys [][]byte
+	c := bd.Cursor()
+	for k, v := c.First(); k != nil && (limit <= 0 || len(keys) < limit); k, v = c.Next() {
+		if b.expired(v) {
+			continue
+		}
+		keys = append(keys, append([]byte{}, b.userKey(k)...))
+	}
+	return keys, nil
+}
+
+// SeqKeys returns up to limit items in sequence order with copies of their
+// keys, or all items if limit is not positive. Data of entries is not set.
+func (b *Bucket) SeqKeys(limit int) ([]Entry, error) {
+	var entries []Entry
+	c := b.Cursor()
+	for ok := c.First(); ok && (limit <= 0 || len(entries) < limit); ok = c.Next() {
+		entries = append(entries, Entry{Seq: c.Seq(), Key: append([]byte{}, c.Key()...)})
+	}
+	return entries, c.Err()
+}
+
+// ReadRange returns copies of up to limit items with sequence numbers between
+// minSeq and maxSeq, inclusive, in sequence order, or all of them if limit is
+// not positive. Zero maxSeq means no upper bound.
+func (b *Bucket) ReadRange(minSeq, maxSeq uint64, limit int) ([]Entry, error) {
+	if limit < 0 {
+		limit = 0
+	}
+	var entries []Entry
+	c := b.CursorOpts(CursorOptions{Min: minSeq, Max: maxSeq, Limit: limit})
+	for ok := c.First(); ok; ok = c.Next() {
+		e, err := c.Entry()
+		if err != nil {
+			return nil, opError("get", c.Key(), c.Seq(), err)
+		}
+		entries = append(entries, e.Clone())
+	}
+	return entries, c.Err()
+}
+
+// PageByKey returns copies of up to limit items in key order with keys
+// greater than afterKey, or all of them if limit is not positive. Nil
+// afterKey starts from the first key. Pass key of the last returned entry
+// to get the next page.
+func (b *Bucket) PageByKey(afterKey []byte, limit int) ([]Entry, error) {
+	bd := b.bucket(bucketNameData)
+	if bd == nil {
+		return nil, nil
+	}
+
+	var entries []Entry
+	c := bd.Cursor()
+	k, v := c.First()
+	if afterKey != nil {
+		afterKey = b.storeKey(afterKey)
+		if k, v = c.Seek(afterKey); bytes.Equal(k, afterKey) {
+			k, v = c.Next()
+		}
+	}
+	for ; k != nil && (limit <= 0 || len(entries) < limit); k, v = c.Next() {
+		if b.expired(v) {
+			continue
+		}
+		dv, err := b.decode(k, v)
+		if err != nil {
+			return nil, opError("get", k, 0, err)
+		}
+		entries = append(entries, Entry{Seq: dv.Seq(), Key: b.userKey(k), Data: dv.Data()}.Clone())
+	}
+	return entries, nil
+}
@@ -0,0 +1,116 @@
+package boltseq
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestBucket_keys(t *testing.T) {
+	b := NewMemBucket()
+	for _, k := range []string{"c", "a", "b"} {
+		if _, err := b.Put([]byte(k), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	keys, err := b.Keys(0)
+	if err != nil || fmt.Sprintf("%s", keys) != "[a b c]" {
+		t.Fatalf("%s %v", keys, err)
+	}
+	if keys, _ := b.Keys(2); len(keys) != 2 {
+		t.Fatal(keys)
+	}
+
+	entries, err := b.SeqKeys(2)
+	if err != nil || len(entries) != 2 || entries[0].Seq != 1 || string(entries[0].Key) != "c" || string(entries[1].Key) != "a" {
+		t.Fatal(entries, err)
+	}
+}
+
+func TestBucket_keysTTL(t *testing.T) {
+	now := time.Unix(1000, 0)
+	b := NewMemBucket()
+	b.Now = func() time.Time { return now }
+	for i, k := range []string{"a", "b", "c"} {
+		if _, err := b.PutTTL([]byte(k), nil, time.Duration(i+1)*time.Second); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now = now.Add(1500 * time.Millisecond)
+	if keys, err := b.Keys(0); err != nil || fmt.Sprintf("%s", keys) != "[b c]" {
+		t.Fatalf("%s %v", keys, err)
+	}
+	if keys, _ := b.Keys(1); fmt.Sprintf("%s", keys) != "[b]" {
+		t.Fatalf("%s", keys)
+	}
+}
+
+func TestBucket_ReadRange(t *testing.T) {
+	b := NewMemBucket()
+	for _, k := range []string{"a", "b", "c", "d", "e"} {
+		if _, err := b.Put([]byte(k), []byte(k+k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		min, max uint64
+		limit    int
+		want     string
+	}{
+		{0, 0, 0, "a1:aa b2:bb c3:cc d4:dd e5:ee "},
+		{2, 4, 0, "b2:bb c3:cc d4:dd "},
+		{2, 0, 2, "b2:bb c3:cc "},
+		{4, 2, 0, ""},
+		{6, 0, 0, ""},
+	}
+	for _, tt := range tests {
+		entries, err := b.ReadRange(tt.min, tt.max, tt.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var s string
+		for _, e := range entries {
+			s += fmt.Sprintf("%s%d:%s ", e.Key, e.Seq, e.Data)
+		}
+		if s != tt.want {
+			t.Errorf("ReadRange(%d, %d, %d) = %q, want %q", tt.min, tt.max, tt.limit, s, tt.want)
+		}
+	}
+}
+
+func TestBucket_PageByKey(t *testing.T) {
+	b := NewMemBucket()
+	for _, k := range []string{"c", "a", "e", "b", "d"} {
+		if _, err := b.Put([]byte(k), []byte(k+k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var pages []string
+	var after []byte
+	for {
+		entries, err := b.PageByKey(after, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) == 0 {
+			break
+		}
+		var s string
+		for _, e := range entries {
+			s += fmt.Sprintf("%s%d:%s ", e.Key, e.Seq, e.Data)
+		}
+		pages = append(pages, s)
+		after = entries[len(entries)-1].Key
+	}
+	if s := fmt.Sprint(pages); s != "[a2:aa b4:bb  c1:cc d5:dd  e3:ee ]" {
+		t.Fatal(s)
+	}
+
+	if entries, err := b.PageByKey([]byte("bb"), 0); err != nil || len(entries) != 3 || string(entries[0].Key) != "c" {
+		t.Fatal(entries, err)
+	}
+}
@@ -0,0 +1,118 @@
+package boltseq
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"time"
+)
+
+// marshalHeader returns header of value v to be marshalled. Values with data
+// still encoded for storage, e.g. compressed, can't be marshalled.
+func marshalHeader(v Value) (header, int, error) {
+	h, n, ok := parseHeader(v)
+	if !ok || h.flags&flagsEncoded != 0 {
+		return h, 0, ErrInvalidValue
+	}
+	return h, n, nil
+}
+
+// MarshalBinary encodes value as 8-byte big-endian sequence number followed
+// by data, or in the extended format described in header.go if the value has
+// expiry time, flags or origin.
+func (v Value) MarshalBinary() ([]byte, error) {
+	h, n, err := marshalHeader(v)
+	if err != nil {
+		return nil, err
+	}
+	if h.flags == 0 {
+		return newValue(v.Seq(), v[n:]), nil
+	}
+	return append([]byte{}, v...), nil
+}
+
+// UnmarshalBinary decodes value encoded by MarshalBinary.
+func (v *Value) UnmarshalBinary(p []byte) error {
+	if _, _, err := marshalHeader(p); err != nil {
+		return err
+	}
+	*v = append(Value{}, p...)
+	return nil
+}
+
+// valueJSON is JSON representation of Value, data and origin encoded with
+// base64. Fields of the header are omitted if not set.
+type valueJSON struct {
+	Seq     uint64     `json:"seq"`
+	Data    []byte     `json:"data"`
+	Expires *time.Time `json:"expires,omitempty"`
+	Flags   byte       `json:"flags,omitempty"`
+	Origin  []byte     `json:"origin,omitempty"`
+}
+
+// MarshalJSON encodes value as object with seq and base64-encoded data,
+// along with expiry time, flags and origin, if set.
+func (v Value) MarshalJSON() ([]byte, error) {
+	h, n, err := marshalHeader(v)
+	if err != nil {
+		return nil, err
+	}
+	vj := valueJSON{Seq: v.Seq(), Data: v[n:], Flags: h.user, Origin: h.origin}
+	if h.flags&flagExpiry != 0 {
+		t := time.Unix(0, h.expires).UTC()
+		vj.Expires = &t
+	}
+	return json.Marshal(vj)
+}
+
+// UnmarshalJSON decodes value encoded by MarshalJSON.
+func (v *Value) UnmarshalJSON(p []byte) error {
+	var vj valueJSON
+	if err := json.Unmarshal(p, &vj); err != nil {
+		return err
+	}
+	if vj.Seq >= seqExtBit {
+		return ErrInvalidSeq
+	}
+	var h header
+	if vj.Expires != nil {
+		h.flags |= flagExpiry
+		h.expires = vj.Expires.UnixNano()
+	}
+	h.setUser(vj.Flags)
+	if err := h.setOrigin(vj.Origin); err != nil {
+		return err
+	}
+	nv := make(Value, 8+h.size()+len(vj.Data))
+	h.putValue(nv, vj.Seq, vj.Data)
+	*v = nv
+	return nil
+}
+
+// MarshalBinary encodes entry as uvarint sequence number, uvarint key length,
+// key and data.
+func (e Entry) MarshalBinary() ([]byte, error) {
+	p := make([]byte, 2*binary.MaxVarintLen64, 2*binary.MaxVarintLen64+len(e.Key)+len(e.Data))
+	n := binary.PutUvarint(p, e.Seq)
+	n += binary.PutUvarint(p[n:], uint64(len(e.Key)))
+	p = append(p[:n], e.Key...)
+	return append(p, e.Data...), nil
+}
+
+// UnmarshalBinary decodes entry encoded by MarshalBinary.
+// Key and data are copied.
+func (e *Entry) UnmarshalBinary(p []byte) error {
+	seq, n := binary.Uvarint(p)
+	if n <= 0 {
+		return ErrInvalidValue
+	}
+	p = p[n:]
+	size, n := binary.Uvarint(p)
+	if n <= 0 || uint64(len(p)-n) < size {
+		return ErrInvalidValue
+	}
+	p = p[n:]
+	e.Seq = seq
+	e.Key = append([]byte{}, p[:size]...)
+	e.Data = append([]byte{}, p[size:]...)
+	return nil
+}
@@ -0,0 +1,91 @@
+package boltseq
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValue_marshal(t *testing.T) {
+	v := newValue(42, []byte{0, 1, 0xff})
+	p, err := v.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var u Value
+	if err := u.UnmarshalBinary(p); err != nil || !reflect.DeepEqual(u, v) {
+		t.Fatal(u, err)
+	}
+	if err := u.UnmarshalBinary([]byte{1}); err != ErrInvalidValue {
+		t.Fatal(err)
+	}
+
+	p, err = json.Marshal(v)
+	if err != nil || string(p) != `{"seq":42,"data":"AAH/"}` {
+		t.Fatal(string(p), err)
+	}
+	u = nil
+	if err := json.Unmarshal(p, &u); err != nil || !reflect.DeepEqual(u, v) {
+		t.Fatal(u, err)
+	}
+}
+
+func TestValue_marshalHeader(t *testing.T) {
+	h := header{flags: flagExpiry, expires: time.Unix(1000, 5).UnixNano()}
+	h.setUser(3)
+	if err := h.setOrigin([]byte("w1")); err != nil {
+		t.Fatal(err)
+	}
+	v := make(Value, 8+h.size()+2)
+	h.putValue(v, 42, []byte("ab"))
+
+	p, err := v.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var u Value
+	if err := u.UnmarshalBinary(p); err != nil || !reflect.DeepEqual(u, v) {
+		t.Fatal(u, err)
+	}
+
+	p, err = json.Marshal(v)
+	if err != nil || string(p) != `{"seq":42,"data":"YWI=","expires":"1970-01-01T00:16:40.000000005Z","flags":3,"origin":"dzE="}` {
+		t.Fatal(string(p), err)
+	}
+	u = nil
+	if err := json.Unmarshal(p, &u); err != nil || !reflect.DeepEqual(u, v) {
+		t.Fatal(u, err)
+	}
+
+	// Data encoded for storage can't be marshalled
+	h = header{flags: flagCompressed}
+	v = make(Value, 8+h.size())
+	h.putValue(v, 1, nil)
+	if _, err := v.MarshalBinary(); err != ErrInvalidValue {
+		t.Fatal(err)
+	}
+	if err := u.UnmarshalBinary(v); err != ErrInvalidValue {
+		t.Fatal(err)
+	}
+}
+
+func TestEntry_marshal(t *testing.T) {
+	e := Entry{Seq: 300, Key: []byte("key"), Data: []byte{0, 1}}
+	p, err := e.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var u Entry
+	if err := u.UnmarshalBinary(p); err != nil || !reflect.DeepEqual(u, e) {
+		t.Fatal(u, err)
+	}
+	if err := u.UnmarshalBinary(p[:3]); err != ErrInvalidValue {
+		t.Fatal(err)
+	}
+
+	p, err = json.Marshal(e)
+	if err != nil || string(p) != `{"seq":300,"key":"a2V5","data":"AAE="}` {
+		t.Fatal(string(p), err)
+	}
+}
@@ -0,0 +1,159 @@
+package boltseq
+
+import (
+	"bytes"
+	"sort"
+)
+
+// NewMemBucket creates a boltseq bucket held in memory, useful for tests of code
+// using boltseq without setting up a bolt database. Memory buckets have no
+// transactions: every change is applied immediately and cannot be rolled back.
+// They are not safe for concurrent use.
+func NewMemBucket() *Bucket {
+	return NewStoreBucket(&memBucket{})
+}
+
+type memItem struct {
+	key   []byte
+	value []byte
+}
+
+// memBucket implements both Store and KVBucket as a sorted slice of items.
+type memBucket struct {
+	items   []memItem
+	seq     uint64
+	buckets map[string]*memBucket
+}
+
+// Bucket returns nested bucket, or nil if it doesn't exist.
+func (m *memBucket) Bucket(name []byte) KVBucket {
+	if mb := m.buckets[string(name)]; mb != nil {
+		return mb
+	}
+	return nil
+}
+
+func (m *memBucket) CreateBucketIfNotExists(name []byte) (KVBucket, error) {
+	if mb := m.buckets[string(name)]; mb != nil {
+		return mb, nil
+	}
+	if m.buckets == nil {
+		m.buckets = make(map[string]*memBucket)
+	}
+	mb := &memBucket{}
+	m.buckets[string(name)] = mb
+	return mb, nil
+}
+
+// search returns position of the first item with key >= k.
+func (m *memBucket) search(k []byte) int {
+	return sort.Search(len(m.items), func(n int) bool {
+		return bytes.Compare(m.items[n].key, k) >= 0
+	})
+}
+
+func (m *memBucket) Get(key []byte) []byte {
+	n := m.search(key)
+	if n < len(m.items) && bytes.Equal(m.items[n].key, key) {
+		return m.items[n].value
+	}
+	return nil
+}
+
+func (m *memBucket) Put(key, value []byte) error {
+	item := memItem{
+		key:   append([]byte{}, key...),
+		value: append([]byte{}, value...),
+	}
+
+	n := m.search(key)
+	if n < len(m.items) && bytes.Equal(m.items[n].key, key) {
+		m.items[n] = item
+		return nil
+	}
+
+	m.items = append(m.items, memItem{})
+	copy(m.items[n+1:], m.items[n:])
+	m.items[n] = item
+	return nil
+}
+
+func (m *memBucket) Delete(key []byte) error {
+	n := m.search(key)
+	if n < len(m.items) && bytes.Equal(m.items[n].key, key) {
+		m.items = append(m.items[:n], m.items[n+1:]...)
+	}
+	return nil
+}
+
+func (m *memBucket) NextSequence() (uint64, error) {
+	m.seq++
+	return m.seq, nil
+}
+
+func (m *memBucket) Sequence() uint64 {
+	return m.seq
+}
+
+func (m *memBucket) SetSequence(seq uint64) error {
+	m.seq = seq
+	return nil
+}
+
+func (m *memBucket) Cursor() KVCursor {
+	return &memCursor{m: m}
+}
+
+// memCursor remembers the current key rather than position, so it stays
+// valid while the bucket is modified.
+type memCursor struct {
+	m   *memBucket
+	key []byte
+}
+
+func (c *memCursor) at(n int) ([]byte, []byte) {
+	if n < 0 || n >= len(c.m.items) {
+		c.key = nil
+		return nil, nil
+	}
+	it := c.m.items[n]
+	c.key = it.key
+	return it.key, it.value
+}
+
+func (c *memCursor) First() ([]byte, []byte) {
+	return c.at(0)
+}
+
+func (c *memCursor) Last() ([]byte, []byte) {
+	return c.at(len(c.m.items) - 1)
+}
+
+func (c *memCursor) Next() ([]byte, []byte) {
+	if c.key == nil {
+		return nil, nil
+	}
+	n := c.m.search(c.key)
+	if n < len(c.m.items) && bytes.Equal(c.m.items[n].key, c.key) {
+		n++
+	}
+	return c.at(n)
+}
+
+func (c *memCursor) Prev() ([]byte, []byte) {
+	if c.key == nil {
+		return nil, nil
+	}
+	return c.at(c.m.search(c.key) - 1)
+}
+
+func (c *memCursor) Seek(seek []byte) ([]byte, []byte) {
+	return c.at(c.m.search(seek))
+}
+
+func (c *memCursor) Delete() error {
+	if c.key == nil {
+		return nil
+	}
+	return c.m.Delete(c.key)
+}
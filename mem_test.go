@@ -0,0 +1,63 @@
+package boltseq
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMemBucket(t *testing.T) {
+	b := NewMemBucket()
+
+	for n := 0; n < 10; n++ {
+		seq, err := b.Put([]byte(fmt.Sprint(9-n)), []byte(fmt.Sprint(n)))
+		if err != nil || seq != uint64(n+1) {
+			t.Fatal(seq, err)
+		}
+	}
+	if _, err := b.Put([]byte("0"), []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := b.Get([]byte("0")); v.Seq() != 11 || string(v.Data()) != "x" {
+		t.Fatal(v)
+	}
+	if k := b.GetSeq(10); k != nil {
+		t.Fatal(k)
+	}
+
+	// Delete every other item while iterating
+	c := b.Cursor()
+	for ok := c.First(); ok; ok = c.Next() {
+		if c.Seq()%2 == 0 {
+			if err := c.Delete(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	var seqs []uint64
+	err := b.ForEach(func(seq uint64, key, data []byte) error {
+		seqs = append(seqs, seq)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(seqs) != "[1 3 5 7 9 11]" {
+		t.Fatal(seqs)
+	}
+}
+
+func TestNewStoreBucket(t *testing.T) {
+	s := &memBucket{}
+	if _, err := NewStoreBucket(s).Put([]byte("x"), []byte("v")); err != nil {
+		t.Fatal(err)
+	}
+	// Another bucket over the same store sees the data
+	if v := NewStoreBucket(s).Get([]byte("x")); string(v.Data()) != "v" {
+		t.Fatal(v)
+	}
+	if s.Bucket(bucketNameSeq) == nil || s.Bucket(bucketNameData) == nil {
+		t.Fatal("sub-buckets missing")
+	}
+}
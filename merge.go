@@ -0,0 +1,151 @@
+package boltseq
+
+// merger iterates over several cursors at once in order of sequence numbers.
+// Items with equal sequence numbers are ordered by position of their cursor.
+type merger struct {
+	cs  []*Cursor
+	ok  []bool // whether cursor is positioned at an item
+	cur int
+	fwd bool // direction of the last move
+}
+
+// MergeCursors returns cursor iterating over items of all the given cursors,
+// e.g. of different buckets, interleaved in order of sequence numbers. This is
+// meaningful when buckets share a sequence space or their sequence numbers are
+// comparable, e.g. come from TimeSeq. Items with equal sequence numbers are
+// returned in order of their cursors. Data and Delete refer to the cursor of
+// the current item. The merged cursors must not be used directly afterwards.
+func MergeCursors(cs ...*Cursor) *Cursor {
+	return &Cursor{m: &merger{cs: cs, ok: make([]bool, len(cs)), cur: -1}}
+}
+
+// before tells whether item of cursor i precedes item of cursor j.
+func (m *merger) before(i, j int) bool {
+	si, sj := m.cs[i].Seq(), m.cs[j].Seq()
+	return si < sj || si == sj && i < j
+}
+
+// pick selects the first (or last if backward) item among cursors.
+func (m *merger) pick(c *Cursor, fwd bool) bool {
+	m.fwd = fwd
+	m.cur = -1
+	for i := range m.cs {
+		if !m.ok[i] {
+			continue
+		}
+		if m.cur < 0 || m.before(i, m.cur) == fwd {
+			m.cur = i
+		}
+	}
+	if m.cur < 0 {
+		return false
+	}
+	c.seq, c.key = m.cs[m.cur].Seq(), m.cs[m.cur].Key()
+	return true
+}
+
+func (m *merger) first(c *Cursor) bool {
+	for i, sc := range m.cs {
+		m.ok[i] = sc.first()
+	}
+	return m.pick(c, true)
+}
+
+func (m *merger) last(c *Cursor) bool {
+	for i, sc := range m.cs {
+		m.ok[i] = sc.last()
+	}
+	return m.pick(c, false)
+}
+
+func (m *merger) seek(c *Cursor, seq uint64) bool {
+	for i, sc := range m.cs {
+		m.ok[i] = sc.seek(seq)
+	}
+	return m.pick(c, true)
+}
+
+func (m *merger) next(c *Cursor) bool {
+	if m.cur < 0 {
+		return false
+	}
+
+	// After moving backward other cursors point before the current item;
+	// bring them to the first item after it.
+	if !m.fwd {
+		seq := m.cs[m.cur].Seq()
+		for i, sc := range m.cs {
+			if i == m.cur {
+				continue
+			}
+			m.ok[i] = sc.seek(seq)
+			if m.ok[i] && sc.Seq() == seq && i < m.cur {
+				m.ok[i] = sc.next()
+			}
+		}
+	}
+
+	m.ok[m.cur] = m.cs[m.cur].next()
+	return m.pick(c, true)
+}
+
+func (m *merger) prev(c *Cursor) bool {
+	if m.cur < 0 {
+		return false
+	}
+
+	// After moving forward other cursors point after the current item;
+	// bring them to the last item before it.
+	if m.fwd {
+		seq := m.cs[m.cur].Seq()
+		for i, sc := range m.cs {
+			if i == m.cur {
+				continue
+			}
+			if m.ok[i] = sc.seek(seq); !m.ok[i] {
+				m.ok[i] = sc.last()
+			} else if sc.Seq() > seq || sc.Seq() == seq && i > m.cur {
+				m.ok[i] = sc.prev()
+			}
+		}
+	}
+
+	m.ok[m.cur] = m.cs[m.cur].prev()
+	return m.pick(c, false)
+}
+
+// seekKey moves to the item with the given key in the first cursor having it.
+func (m *merger) seekKey(c *Cursor, key []byte) bool {
+	for i, sc := range m.cs {
+		if !sc.SeekKey(key) {
+			continue
+		}
+		if !m.seek(c, sc.Seq()) {
+			return false
+		}
+		for m.cur != i {
+			if !m.next(c) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// current returns cursor positioned at the current item, or nil.
+func (m *merger) current() *Cursor {
+	if m.cur < 0 {
+		return nil
+	}
+	return m.cs[m.cur]
+}
+
+func (m *merger) err() error {
+	for _, sc := range m.cs {
+		if err := sc.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
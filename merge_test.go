@@ -0,0 +1,47 @@
+package boltseq
+
+import (
+	"testing"
+)
+
+func TestMergeCursors(t *testing.T) {
+	b1, b2 := NewMemBucket(), NewMemBucket()
+	for _, k := range []string{"a", "b", "c"} {
+		if _, err := b1.Put([]byte("1"+k), []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, k := range []string{"a", "b"} {
+		if _, err := b2.Put([]byte("2"+k), []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := b1.Delete([]byte("1a")); err != nil {
+		t.Fatal(err)
+	}
+
+	c := MergeCursors(b1.Cursor(), b2.Cursor(), NewMemBucket().Cursor())
+	var keys string
+	for ok := c.First(); ok; ok = c.Next() {
+		keys += string(c.Key()) + " "
+	}
+	if keys != "2a 1b 2b 1c " {
+		t.Fatal(keys)
+	}
+
+	if !c.SeekKey([]byte("2b")) || c.Seq() != 2 {
+		t.Fatal(c.Seq())
+	}
+	if d, err := c.Data(); err != nil || string(d) != "b" {
+		t.Fatal(d, err)
+	}
+	if err := c.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if b2.Get([]byte("2b")) != nil {
+		t.Fatal("not deleted")
+	}
+	if !c.Prev() || string(c.Key()) != "1b" || !c.Prev() || string(c.Key()) != "2a" || c.Prev() {
+		t.Fatal(string(c.Key()))
+	}
+}
@@ -0,0 +1,57 @@
+package boltseq
+
+// metaUserPrefix prefixes keys of user metadata in the meta sub-bucket,
+// keeping them apart from entries used by the package.
+const metaUserPrefix = "user/"
+
+// Meta holds application metadata of a bucket, e.g. schema version or owner,
+// stored in the bucket's meta sub-bucket.
+type Meta struct {
+	b *Bucket
+}
+
+// Meta returns metadata of the bucket.
+func (b *Bucket) Meta() *Meta {
+	return &Meta{b: b}
+}
+
+func metaKey(key string) []byte {
+	return []byte(metaUserPrefix + key)
+}
+
+// Get returns value stored under key, or nil if it doesn't exist.
+func (m *Meta) Get(key string) []byte {
+	bm := m.b.bucket(bucketNameMeta)
+	if bm == nil {
+		return nil
+	}
+	return bm.Get(metaKey(key))
+}
+
+// GetString returns value stored under key as string, or "" if it doesn't exist.
+func (m *Meta) GetString(key string) string {
+	return string(m.Get(key))
+}
+
+// Set stores value under key.
+func (m *Meta) Set(key string, value []byte) error {
+	bm, err := m.b.createBucket(bucketNameMeta)
+	if err != nil {
+		return err
+	}
+	return bm.Put(metaKey(key), value)
+}
+
+// SetString stores string value under key.
+func (m *Meta) SetString(key, value string) error {
+	return m.Set(key, []byte(value))
+}
+
+// Delete deletes value stored under key.
+func (m *Meta) Delete(key string) error {
+	bm := m.b.bucket(bucketNameMeta)
+	if bm == nil {
+		return nil
+	}
+	return bm.Delete(metaKey(key))
+}
@@ -0,0 +1,43 @@
+package boltseq
+
+import "testing"
+
+func TestBucket_meta(t *testing.T) {
+	b := NewMemBucket()
+	m := b.Meta()
+	if v := m.Get("schema"); v != nil {
+		t.Fatal(v)
+	}
+	if err := m.Delete("schema"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.SetString("schema", "v2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Set("size", []byte("user size")); err != nil {
+		t.Fatal(err)
+	}
+	if s := b.Meta().GetString("schema"); s != "v2" {
+		t.Fatal(s)
+	}
+
+	// User entries don't clash with internal ones
+	b.Quota = 100
+	if _, err := b.Put([]byte("a"), []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	if size, ok := b.storedSize(); !ok || size != 12 {
+		t.Fatal(size, ok)
+	}
+	if s := m.GetString("size"); s != "user size" {
+		t.Fatal(s)
+	}
+
+	if err := m.Delete("schema"); err != nil {
+		t.Fatal(err)
+	}
+	if v := m.Get("schema"); v != nil {
+		t.Fatal(v)
+	}
+}
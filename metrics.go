@@ -0,0 +1,19 @@
+package boltseq
+
+import "time"
+
+// Metrics receives observations of bucket operations, e.g. to feed Prometheus
+// histograms and counters. Byte counts are lengths of keys and data involved
+// in the operation. Implementations shared between buckets must be safe for
+// concurrent use.
+type Metrics interface {
+	ObservePut(d time.Duration, bytes int)
+	ObserveGet(d time.Duration, bytes int)
+	ObserveDelete(d time.Duration, bytes int)
+	ObserveCursorStep(d time.Duration, bytes int)
+}
+
+// observe reports duration since start together with byte count n to f.
+func observe(f func(time.Duration, int), start time.Time, n int) {
+	f(time.Since(start), n)
+}
@@ -0,0 +1,55 @@
+package boltseq
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+type countMetrics struct {
+	puts, gets, deletes, steps int
+	bytes                      int
+}
+
+func (m *countMetrics) ObservePut(d time.Duration, n int)    { m.puts++; m.bytes += n }
+func (m *countMetrics) ObserveGet(d time.Duration, n int)    { m.gets++; m.bytes += n }
+func (m *countMetrics) ObserveDelete(d time.Duration, n int) { m.deletes++; m.bytes += n }
+func (m *countMetrics) ObserveCursorStep(d time.Duration, n int) {
+	m.steps++
+	m.bytes += n
+}
+
+func TestBucket_metrics(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	m := &countMetrics{}
+	err = db.Update(func(tx *bolt.Tx) error {
+		b := NewBucket(tx.Bucket(testBucketName))
+		b.Metrics = m
+
+		if _, err := b.Put([]byte("x"), []byte("v")); err != nil {
+			t.Fatal(err)
+		}
+		b.Get([]byte("x"))
+		c := b.Cursor()
+		for ok := c.First(); ok; ok = c.Next() {
+		}
+		return b.Delete([]byte("x"))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.puts != 1 || m.gets != 1 || m.deletes != 1 || m.steps != 2 {
+		t.Fatalf("%+v", m)
+	}
+	if m.bytes != 2+11+1+1 {
+		t.Fatal(m.bytes)
+	}
+}
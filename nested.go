@@ -0,0 +1,86 @@
+package boltseq
+
+import (
+	"sort"
+)
+
+// sub-bucket holding nested boltseq buckets
+var bucketNameSub = []byte("sub")
+
+// SubBucket returns boltseq bucket nested under the key, sharing options with b.
+// Nested buckets live in their own namespace, independent of items of b;
+// they are created on first Put.
+func (b *Bucket) SubBucket(key []byte) *Bucket {
+	sb := NewStoreBucket(nestedStore{parent: nestedStore{parent: b.loc, name: bucketNameSub}, name: key})
+	sb.Options = b.Options
+	return sb
+}
+
+// SubBuckets returns copies of names of nested buckets in key order.
+func (b *Bucket) SubBuckets() ([][]byte, error) {
+	kb := b.bucket(bucketNameSub)
+	if kb == nil {
+		return nil, nil
+	}
+
+	switch kb := kb.(type) {
+	case boltBucket:
+		var names [][]byte
+		err := kb.ForEach(func(k, v []byte) error {
+			if v == nil {
+				names = append(names, append([]byte{}, k...))
+			}
+			return nil
+		})
+		return names, err
+	case *memBucket:
+		var names [][]byte
+		for name := range kb.buckets {
+			names = append(names, []byte(name))
+		}
+		sort.Slice(names, func(i, j int) bool { return string(names[i]) < string(names[j]) })
+		return names, nil
+	}
+	return nil, ErrNestingUnsupported
+}
+
+// asStore returns Store for buckets nested in kb.
+func asStore(kb KVBucket) (Store, error) {
+	switch kb := kb.(type) {
+	case boltBucket:
+		return BoltStore(kb.Bucket), nil
+	case Store:
+		return kb, nil
+	}
+	return nil, ErrNestingUnsupported
+}
+
+// nestedStore is a Store within a named bucket of parent, created when needed.
+type nestedStore struct {
+	parent Store
+	name   []byte
+}
+
+func (s nestedStore) Bucket(name []byte) KVBucket {
+	kb := s.parent.Bucket(s.name)
+	if kb == nil {
+		return nil
+	}
+	st, err := asStore(kb)
+	if err != nil {
+		return nil
+	}
+	return st.Bucket(name)
+}
+
+func (s nestedStore) CreateBucketIfNotExists(name []byte) (KVBucket, error) {
+	kb, err := s.parent.CreateBucketIfNotExists(s.name)
+	if err != nil {
+		return nil, err
+	}
+	st, err := asStore(kb)
+	if err != nil {
+		return nil, err
+	}
+	return st.CreateBucketIfNotExists(name)
+}
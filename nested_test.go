@@ -0,0 +1,63 @@
+package boltseq
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func testSubBuckets(t *testing.T, b *Bucket) {
+	if _, err := b.Put([]byte("x"), []byte("parent")); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"s2", "s1"} {
+		sb := b.SubBucket([]byte(name))
+		if _, err := sb.Put([]byte("x"), []byte(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if v := b.SubBucket([]byte("s1")).Get([]byte("x")); v.Seq() != 1 || string(v.Data()) != "s1" {
+		t.Fatal(v)
+	}
+	if v := b.Get([]byte("x")); string(v.Data()) != "parent" {
+		t.Fatal(v)
+	}
+	if v := b.SubBucket([]byte("nx")).Get([]byte("x")); v != nil {
+		t.Fatal(v)
+	}
+
+	names, err := b.SubBuckets()
+	if err != nil || fmt.Sprintf("%s", names) != "[s1 s2]" {
+		t.Fatalf("%s %v", names, err)
+	}
+
+	// Nesting goes deeper
+	deep := b.SubBucket([]byte("s1")).SubBucket([]byte("d"))
+	if _, err := deep.Put([]byte("y"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if deep.Get([]byte("y")) == nil {
+		t.Fatal("missing")
+	}
+}
+
+func TestBucket_subBucket(t *testing.T) {
+	testSubBuckets(t, NewMemBucket())
+
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		testSubBuckets(t, NewBucket(tx.Bucket(testBucketName)))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,72 @@
+package boltseq
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// metaOffsetPrefix prefixes keys of consumer offsets in the meta sub-bucket.
+const metaOffsetPrefix = "offset/"
+
+// ErrOffsetBehind is returned when committing an offset lower than the
+// one already committed.
+var ErrOffsetBehind = errors.New("offset behind committed one")
+
+// Offsets holds last processed sequence numbers of named consumers of the
+// bucket. Commit them in the transaction processing the items, so progress
+// is stored consistently with its results.
+type Offsets struct {
+	b *Bucket
+}
+
+// Offsets returns consumer offsets of the bucket.
+func (b *Bucket) Offsets() *Offsets {
+	return &Offsets{b: b}
+}
+
+func offsetKey(name string) []byte {
+	return []byte(metaOffsetPrefix + name)
+}
+
+// Commit stores seq as the last item processed by consumer name.
+// Returns ErrOffsetBehind if a higher one is already committed.
+func (o *Offsets) Commit(name string, seq uint64) error {
+	cur, err := o.Resume(name)
+	if err != nil {
+		return err
+	}
+	if seq < cur {
+		return ErrOffsetBehind
+	}
+	bm, err := o.b.createBucket(bucketNameMeta)
+	if err != nil {
+		return err
+	}
+	return bm.Put(offsetKey(name), seqKey(seq))
+}
+
+// Resume returns the last sequence number committed by consumer name, or zero
+// if there is none, so iteration can continue after it, e.g. with Tail.
+func (o *Offsets) Resume(name string) (uint64, error) {
+	bm := o.b.bucket(bucketNameMeta)
+	if bm == nil {
+		return 0, nil
+	}
+	v := bm.Get(offsetKey(name))
+	if v == nil {
+		return 0, nil
+	}
+	if len(v) != 8 {
+		return 0, ErrInvalidValue
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+// Reset deletes the offset of consumer name.
+func (o *Offsets) Reset(name string) error {
+	bm := o.b.bucket(bucketNameMeta)
+	if bm == nil {
+		return nil
+	}
+	return bm.Delete(offsetKey(name))
+}
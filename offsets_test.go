@@ -0,0 +1,43 @@
+package boltseq
+
+import "testing"
+
+func TestBucket_offsets(t *testing.T) {
+	b := NewMemBucket()
+	o := b.Offsets()
+
+	if seq, err := o.Resume("ui"); err != nil || seq != 0 {
+		t.Fatal(seq, err)
+	}
+	if err := o.Commit("ui", 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Commit("indexer", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Commit("ui", 7); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Commit("ui", 6); err != ErrOffsetBehind {
+		t.Fatal(err)
+	}
+
+	if seq, err := o.Resume("ui"); err != nil || seq != 7 {
+		t.Fatal(seq, err)
+	}
+	if seq, err := b.Offsets().Resume("indexer"); err != nil || seq != 2 {
+		t.Fatal(seq, err)
+	}
+
+	// Offsets don't clash with user metadata
+	if v := b.Meta().Get("ui"); v != nil {
+		t.Fatal(v)
+	}
+
+	if err := o.Reset("ui"); err != nil {
+		t.Fatal(err)
+	}
+	if seq, err := o.Resume("ui"); err != nil || seq != 0 {
+		t.Fatal(seq, err)
+	}
+}
@@ -0,0 +1,58 @@
+package boltseq
+
+import (
+	"context"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Put stores key-value pair in the bucket at path within its own read-write
+// transaction. See Bucket.Put and DB.UpdateBucket.
+func Put(db *bolt.DB, path [][]byte, key, value []byte) (seq uint64, err error) {
+	err = NewDB(db).UpdateBucket(path, func(b *Bucket) error {
+		seq, err = b.Put(key, value)
+		return err
+	})
+	return
+}
+
+// Get returns a copy of the value for the key in the bucket at path, read
+// within its own read-only transaction. Returns nil if the key doesn't exist
+// and ErrInvalidValue if the stored value is corrupted.
+func Get(db *bolt.DB, path [][]byte, key []byte) (v Value, err error) {
+	err = NewDB(db).ViewBucket(path, func(b *Bucket) error {
+		bv, err := b.GetValue(key)
+		if err != nil || bv == nil {
+			return err
+		}
+		if !bv.IsValid() {
+			return ErrInvalidValue
+		}
+		v = append(Value(nil), bv...)
+		return nil
+	})
+	return
+}
+
+// Delete deletes the key from the bucket at path within its own read-write transaction.
+func Delete(db *bolt.DB, path [][]byte, key []byte) error {
+	return NewDB(db).UpdateBucket(path, func(b *Bucket) error {
+		return b.Delete(key)
+	})
+}
+
+// ForEach calls fn for every item of the bucket at path, within a single
+// read-only transaction. Slices passed to fn are only valid until fn returns.
+func ForEach(db *bolt.DB, path [][]byte, fn func(seq uint64, key, data []byte) error) error {
+	return NewDB(db).ViewBucket(path, func(b *Bucket) error {
+		return b.ForEach(fn)
+	})
+}
+
+// ForEachCtx is like ForEach, but stops with ctx.Err() once ctx gets cancelled.
+// See Bucket.ForEachCtx.
+func ForEachCtx(ctx context.Context, db *bolt.DB, path [][]byte, fn func(ctx context.Context, seq uint64, key, data []byte) error) error {
+	return NewDB(db).ViewBucket(path, func(b *Bucket) error {
+		return b.ForEachCtx(ctx, fn)
+	})
+}
@@ -0,0 +1,43 @@
+package boltseq
+
+import (
+	"os"
+	"testing"
+)
+
+func TestOneShot(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	path := [][]byte{testBucketName}
+	for n, k := range []string{"x", "y", "z"} {
+		seq, err := Put(db, path, []byte(k), []byte("v"+k))
+		if err != nil || seq != uint64(n+1) {
+			t.Fatal(seq, err)
+		}
+	}
+
+	if err := Delete(db, path, []byte("y")); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := Get(db, path, []byte("z"))
+	if err != nil || v.Seq() != 3 || string(v.Data()) != "vz" {
+		t.Fatal(v, err)
+	}
+	if v, err := Get(db, path, []byte("y")); err != nil || v != nil {
+		t.Fatal(v, err)
+	}
+
+	var keys string
+	err = ForEach(db, path, func(seq uint64, key, data []byte) error {
+		keys += string(key)
+		return nil
+	})
+	if err != nil || keys != "xz" {
+		t.Fatal(keys, err)
+	}
+}
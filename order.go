@@ -0,0 +1,177 @@
+package boltseq
+
+import (
+	"bytes"
+	"errors"
+)
+
+// ErrKeyNotFound is returned when an operation requires a key that doesn't exist.
+var ErrKeyNotFound = errors.New("key not found")
+
+// MoveBefore moves the key so it directly precedes refKey in sequence order.
+// The key is given a sequence number between refKey and its predecessor; if
+// there is no room, refKey and items directly following it are renumbered.
+func (b *Bucket) MoveBefore(key, refKey []byte) error {
+	return opError("move", key, 0, b.move(key, refKey, true))
+}
+
+// MoveAfter moves the key so it directly follows refKey in sequence order.
+// See MoveBefore.
+func (b *Bucket) MoveAfter(key, refKey []byte) error {
+	return opError("move", key, 0, b.move(key, refKey, false))
+}
+
+func (b *Bucket) move(key, refKey []byte, before bool) error {
+	if b.AppendOnly {
+		return ErrAppendOnly
+	}
+	if bytes.Equal(key, refKey) {
+		return nil
+	}
+
+	v, ref := b.get(key), b.get(refKey)
+	if v == nil || ref == nil {
+		return ErrKeyNotFound
+	}
+	if !v.IsValid() || !ref.IsValid() {
+		return ErrInvalidValue
+	}
+
+	bs := b.bucket(bucketNameSeq)
+	if bs == nil {
+		return ErrInvalidBucket
+	}
+	if err := bs.Delete(v.seqBytes()); err != nil {
+		return err
+	}
+
+	// Find neighbours between which the key goes, 0 meaning none
+	var low, high uint64
+	c := b.rawCursor()
+	if !c.Seek(ref.Seq()) {
+		if err := c.Err(); err != nil {
+			return err
+		}
+		return ErrSeqMismatch
+	}
+	if before {
+		high = ref.Seq()
+		if c.Prev() {
+			low = c.Seq()
+		}
+	} else {
+		low = ref.Seq()
+		if c.Next() {
+			high = c.Seq()
+		}
+	}
+	if err := c.Err(); err != nil {
+		return err
+	}
+
+	var seq uint64
+	switch {
+	case high == 0:
+		var err error
+		if seq, err = b.nextSeq(bs); err != nil {
+			return err
+		}
+	case high-low > 1:
+		seq = low + (high-low)/2
+	default:
+		if err := b.shiftUp(high); err != nil {
+			return err
+		}
+		seq = high
+	}
+
+	return b.reseq(key, v, seq)
+}
+
+// shiftUp increments sequence numbers of the run of consecutive items
+// starting at seq, making seq free.
+func (b *Bucket) shiftUp(seq uint64) error {
+	var keys [][]byte
+	c := b.rawCursor()
+	for ok := c.Seek(seq); ok && c.Seq() == seq+uint64(len(keys)); ok = c.Next() {
+		keys = append(keys, append([]byte{}, c.Key()...))
+	}
+	if err := c.Err(); err != nil {
+		return err
+	}
+
+	if bs := b.bucket(bucketNameSeq); bs.Sequence() < seq+uint64(len(keys)) {
+		if err := bs.SetSequence(seq + uint64(len(keys))); err != nil {
+			return err
+		}
+	}
+
+	for n := len(keys) - 1; n >= 0; n-- {
+		v := b.get(keys[n])
+		if !v.IsValid() {
+			return ErrInvalidValue
+		}
+		if err := b.bucket(bucketNameSeq).Delete(v.seqBytes()); err != nil {
+			return err
+		}
+		if err := b.reseq(keys[n], v, v.Seq()+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reseq stores value v of the key under a new sequence number.
+// The old seq->key mapping must be already removed.
+func (b *Bucket) reseq(key []byte, v Value, seq uint64) error {
+	if err := b.logUpdate(key, key, v, seq); err != nil {
+		return err
+	}
+	skey := b.storeKey(key)
+	nv, err := b.rebind(v, skey, skey, seq)
+	if err != nil {
+		return err
+	}
+
+	if err := b.bucket(bucketNameSeq).Put(nv.seqBytes(), skey); err != nil {
+		return err
+	}
+	if err := b.bucket(bucketNameData).Put(skey, nv); err != nil {
+		return err
+	}
+	return b.verify([][]byte{key})
+}
+
+// Touch gives the key a new sequence number, moving it to the end of
+// iteration order, without changing its data. Returns the new sequence number.
+func (b *Bucket) Touch(key []byte) (uint64, error) {
+	seq, err := b.touch(key)
+	return seq, opError("touch", key, 0, err)
+}
+
+func (b *Bucket) touch(key []byte) (uint64, error) {
+	if b.AppendOnly {
+		return 0, ErrAppendOnly
+	}
+	v := b.get(key)
+	if v == nil {
+		return 0, ErrKeyNotFound
+	}
+	if !v.IsValid() {
+		return 0, ErrInvalidValue
+	}
+
+	bs := b.bucket(bucketNameSeq)
+	if bs == nil {
+		return 0, ErrInvalidBucket
+	}
+	if err := bs.Delete(v.seqBytes()); err != nil {
+		return 0, err
+	}
+
+	seq, err := b.nextSeq(bs)
+	if err != nil {
+		return 0, err
+	}
+	return seq, b.reseq(key, v, seq)
+}
@@ -0,0 +1,85 @@
+package boltseq
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func orderOf(t *testing.T, b *Bucket) string {
+	var s string
+	err := b.ForEach(func(seq uint64, key, data []byte) error {
+		s += fmt.Sprintf("%s%d ", key, seq)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestBucket_move(t *testing.T) {
+	b := NewMemBucket()
+	for _, k := range []string{"a", "b", "c", "d"} {
+		if _, err := b.Put([]byte(k), []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		key, ref string
+		before   bool
+		exp      string
+	}{
+		{"d", "b", true, "a1 d2 b3 c4 "},  // no room, b and c are renumbered
+		{"a", "c", false, "d2 b3 c4 a5 "}, // moved after the last item
+		{"a", "d", true, "a1 d2 b3 c4 "},  // gap at the beginning
+		{"c", "a", false, "a1 c2 d3 b4 "}, // no room after a
+		{"b", "a", true, "b1 a2 c3 d4 "},  // whole run renumbered
+		{"b", "c", false, "a2 c3 b4 d5 "}, // last item renumbered
+	}
+	for _, tt := range tests {
+		move := b.MoveAfter
+		if tt.before {
+			move = b.MoveBefore
+		}
+		if err := move([]byte(tt.key), []byte(tt.ref)); err != nil {
+			t.Fatal(err)
+		}
+		if s := orderOf(t, b); s != tt.exp {
+			t.Fatalf("%s %s: %s", tt.key, tt.ref, s)
+		}
+	}
+
+	if err := b.MoveBefore([]byte("nx"), []byte("a")); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatal(err)
+	}
+	if v := b.Get([]byte("c")); v.Seq() != 3 || string(v.Data()) != "c" {
+		t.Fatal(v)
+	}
+	if seq, _ := b.Put([]byte("e"), nil); seq != 6 {
+		t.Fatal(seq)
+	}
+}
+
+func TestBucket_touch(t *testing.T) {
+	b := NewMemBucket()
+	for _, k := range []string{"a", "b", "c"} {
+		if _, err := b.Put([]byte(k), []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if seq, err := b.Touch([]byte("a")); err != nil || seq != 4 {
+		t.Fatal(seq, err)
+	}
+	if s := orderOf(t, b); s != "b2 c3 a4 " {
+		t.Fatal(s)
+	}
+	if d := b.Get([]byte("a")).Data(); string(d) != "a" {
+		t.Fatal(d)
+	}
+	if _, err := b.Touch([]byte("nx")); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatal(err)
+	}
+}
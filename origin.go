@@ -0,0 +1,56 @@
+package boltseq
+
+import "errors"
+
+// MaxOriginSize is the maximum size of origin metadata of an item.
+const MaxOriginSize = 255
+
+// ErrOriginTooLarge is returned when origin metadata exceeds MaxOriginSize.
+var ErrOriginTooLarge = errors.New("origin metadata too large")
+
+// Origin returns origin metadata of the value, see PutOrigin.
+func (v Value) Origin() []byte {
+	h, _, _ := parseHeader(v)
+	return h.origin
+}
+
+// PutOrigin is like Put, additionally storing origin metadata of the item,
+// e.g. writer or request ID, kept apart from its data. Origin is limited
+// to MaxOriginSize bytes.
+func (b *Bucket) PutOrigin(key, value, origin []byte) (uint64, error) {
+	var h header
+	if err := h.setOrigin(origin); err != nil {
+		return 0, opError("put", key, 0, err)
+	}
+	return b.putHeader(key, value, 0, h)
+}
+
+// setOrigin sets origin metadata.
+func (h *header) setOrigin(origin []byte) error {
+	if len(origin) > MaxOriginSize {
+		return ErrOriginTooLarge
+	}
+	h.origin = origin
+	if len(origin) != 0 {
+		h.flags |= flagOrigin
+	} else {
+		h.flags &^= flagOrigin
+	}
+	return nil
+}
+
+// Origin returns origin metadata of the current item.
+func (c *Cursor) Origin() []byte {
+	if c.m != nil {
+		if cur := c.m.current(); cur != nil {
+			return cur.Origin()
+		}
+		return nil
+	}
+	v, _ := c.dp.Get(c.key)
+	origin := Value(v).Origin()
+	if c.b.Detach && origin != nil {
+		return append([]byte{}, origin...)
+	}
+	return origin
+}
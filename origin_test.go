@@ -0,0 +1,44 @@
+package boltseq
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestBucket_origin(t *testing.T) {
+	b := NewMemBucket()
+	b.Compression = CompressAlways
+
+	if _, err := b.PutOrigin([]byte("a"), []byte("1"), []byte("writer-1")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Put([]byte("b"), []byte("2")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.PutOrigin([]byte("x"), nil, bytes.Repeat([]byte("o"), MaxOriginSize+1)); !errors.Is(err, ErrOriginTooLarge) {
+		t.Fatal(err)
+	}
+
+	if v := b.Get([]byte("a")); string(v.Origin()) != "writer-1" || string(v.Data()) != "1" {
+		t.Fatal(v)
+	}
+	if v := b.Get([]byte("b")); v.Origin() != nil || string(v.Data()) != "2" {
+		t.Fatal(v)
+	}
+
+	// Origin is kept along with other header fields
+	if err := b.SetFlags([]byte("a"), flagAcked); err != nil {
+		t.Fatal(err)
+	}
+	c := b.Cursor()
+	if !c.First() || string(c.Origin()) != "writer-1" || c.Flags() != flagAcked {
+		t.Fatal(string(c.Origin()), c.Flags())
+	}
+	if data, err := c.Data(); err != nil || string(data) != "1" {
+		t.Fatal(data, err)
+	}
+	if c.Next(); c.Origin() != nil {
+		t.Fatal(c.Origin())
+	}
+}
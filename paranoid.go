@@ -0,0 +1,45 @@
+package boltseq
+
+import "bytes"
+
+// verify checks that the given keys and sequence numbers, touched by a
+// modification, are consistent across sub-buckets, if Paranoid is set.
+// Returns CorruptionError describing the first inconsistency found.
+func (b *Bucket) verify(keys [][]byte, seqs ...uint64) error {
+	if !b.Paranoid {
+		return nil
+	}
+	bd, bs := b.bucket(bucketNameData), b.bucket(bucketNameSeq)
+	if bd == nil || bs == nil {
+		return nil
+	}
+
+	for _, key := range keys {
+		key = b.storeKey(key)
+		v := Value(bd.Get(key))
+		if v == nil {
+			continue
+		}
+		seq, ok := v.SeqOK()
+		if !ok {
+			return &CorruptionError{Key: key, Err: ErrInvalidValue}
+		}
+		if k := bs.Get(seqKey(seq)); !bytes.Equal(k, key) {
+			return &CorruptionError{Seq: seq, Key: key, Err: ErrSeqMismatch}
+		}
+	}
+	for _, seq := range seqs {
+		key := bs.Get(seqKey(seq))
+		if key == nil {
+			continue
+		}
+		v := Value(bd.Get(key))
+		if v == nil {
+			return &CorruptionError{Seq: seq, Key: key, Err: ErrInvalidKey}
+		}
+		if vseq, ok := v.SeqOK(); !ok || vseq != seq {
+			return &CorruptionError{Seq: seq, Key: key, Err: ErrSeqMismatch}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package boltseq
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBucket_paranoid(t *testing.T) {
+	b := NewMemBucket()
+	b.Paranoid = true
+	for _, k := range []string{"a", "b", "c"} {
+		if _, err := b.Put([]byte(k), []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := b.Put([]byte("a"), []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Rename([]byte("b"), []byte("d")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.MoveBefore([]byte("a"), []byte("c")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Delete([]byte("d")); err != nil {
+		t.Fatal(err)
+	}
+	if s := orderOf(t, b); s != "a3 c4 " {
+		t.Fatal(s)
+	}
+
+	// Make data entry of "a" point to a sequence number without mapping
+	v := b.get([]byte("a")).Clone()
+	setSeq(v, 7)
+	if err := b.bucket(bucketNameData).Put([]byte("a"), v); err != nil {
+		t.Fatal(err)
+	}
+
+	err := b.SetFlags([]byte("a"), flagAcked)
+	var cerr *CorruptionError
+	if !errors.As(err, &cerr) || cerr.Seq != 7 || string(cerr.Key) != "a" || !errors.Is(err, ErrSeqMismatch) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,55 @@
+package boltseq
+
+import (
+	"sort"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// OrderFunc returns rank of an item of a plain bucket. Items are given
+// sequence numbers in order of increasing rank, e.g. a timestamp stored in value.
+type OrderFunc func(key, value []byte) (uint64, error)
+
+// FromPlainBucket imports items of an ordinary bolt bucket src into dst.
+// Sequence numbers are assigned in key order if order is nil, or in order
+// of ranks returned by order otherwise, items of equal rank in key order.
+// Nested buckets of src are skipped.
+func FromPlainBucket(src *bolt.Bucket, dst *Bucket, order OrderFunc) error {
+	if order == nil {
+		return src.ForEach(func(k, v []byte) error {
+			if v == nil {
+				return nil
+			}
+			_, err := dst.Put(k, v)
+			return err
+		})
+	}
+
+	type item struct {
+		rank uint64
+		key  []byte
+	}
+	var items []item
+	err := src.ForEach(func(k, v []byte) error {
+		if v == nil {
+			return nil
+		}
+		rank, err := order(k, v)
+		if err != nil {
+			return err
+		}
+		items = append(items, item{rank: rank, key: k})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].rank < items[j].rank })
+	for _, it := range items {
+		if _, err := dst.Put(it.key, src.Get(it.key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package boltseq
+
+import (
+	"os"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestFromPlainBucket(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		src, err := tx.CreateBucket([]byte("plain"))
+		if err != nil {
+			return err
+		}
+		for k, v := range map[string]string{"a": "3", "b": "1", "c": "2"} {
+			if err := src.Put([]byte(k), []byte(v)); err != nil {
+				return err
+			}
+		}
+		if _, err := src.CreateBucket([]byte("nested")); err != nil {
+			return err
+		}
+
+		dst, err := tx.CreateBucket([]byte("bykey"))
+		if err != nil {
+			return err
+		}
+		b := NewBucket(dst)
+		if err := FromPlainBucket(src, b, nil); err != nil {
+			return err
+		}
+		if s := orderOf(t, b); s != "a1 b2 c3 " {
+			t.Fatal(s)
+		}
+
+		dst, err = tx.CreateBucket([]byte("byvalue"))
+		if err != nil {
+			return err
+		}
+		b = NewBucket(dst)
+		err = FromPlainBucket(src, b, func(key, value []byte) (uint64, error) {
+			return uint64(value[0]), nil
+		})
+		if err != nil {
+			return err
+		}
+		if s := orderOf(t, b); s != "b1 c2 a3 " {
+			t.Fatal(s)
+		}
+		if v := b.Get([]byte("a")); string(v.Data()) != "3" {
+			t.Fatal(v)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
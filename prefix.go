@@ -0,0 +1,74 @@
+package boltseq
+
+import (
+	"bytes"
+	"sort"
+)
+
+// PrefixAfter returns copies of up to limit items having keys with the given
+// prefix and sequence numbers greater than seq, in sequence order, or all of
+// them if limit is not positive. It walks only the keys with the prefix in
+// key order, so its cost depends on their number rather than on the number of
+// items after seq. For short prefixes matching most of the bucket, a cursor
+// with a Key filter may be cheaper. Hashed keys, see Options.HashKeys, don't
+// keep the prefix, so their original keys are all checked in addition. Keys
+// are walked in key order, so all of them are checked even with a limit, but
+// only the limit items with the lowest sequence numbers are kept.
+func (b *Bucket) PrefixAfter(prefix []byte, seq uint64, limit int) ([]Entry, error) {
+	bd := b.bucket(bucketNameData)
+	if bd == nil {
+		return nil, nil
+	}
+
+	var entries []Entry
+	add := func(k, v []byte) error {
+		s, ok := Value(v).SeqOK()
+		if !ok {
+			return opError("get", b.userKey(k), 0, ErrInvalidValue)
+		}
+		if s <= seq || b.expired(v) {
+			return nil
+		}
+		if limit > 0 && len(entries) == limit {
+			if s > entries[limit-1].Seq {
+				return nil
+			}
+			entries = entries[:limit-1]
+		}
+		// Keep entries sorted by sequence number
+		n := sort.Search(len(entries), func(i int) bool { return entries[i].Seq > s })
+		entries = append(entries, Entry{})
+		copy(entries[n+1:], entries[n:])
+		entries[n] = Entry{Seq: s, Key: k}
+		return nil
+	}
+	c := bd.Cursor()
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		if b.hashed(k) {
+			continue
+		}
+		if err := add(k, v); err != nil {
+			return nil, err
+		}
+	}
+	if bk := b.bucket(bucketNameKeys); bk != nil && b.hashKeysOver() > 0 {
+		kc := bk.Cursor()
+		for k, key := kc.First(); k != nil; k, key = kc.Next() {
+			if !bytes.HasPrefix(key, prefix) {
+				continue
+			}
+			if err := add(k, bd.Get(k)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for n, e := range entries {
+		v, err := b.decode(b.storeKey(e.Key), b.get(e.Key))
+		if err != nil {
+			return nil, opError("get", e.Key, e.Seq, err)
+		}
+		entries[n] = Entry{Seq: e.Seq, Key: b.userKey(e.Key), Data: v.Data()}.Clone()
+	}
+	return entries, nil
+}
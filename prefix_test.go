@@ -0,0 +1,47 @@
+package boltseq
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBucket_PrefixAfter(t *testing.T) {
+	b := NewMemBucket()
+	for _, k := range []string{"ui/b", "x/a", "ui/a", "u", "ui/c", "uj", "ui/b"} {
+		if _, err := b.Put([]byte(k), []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		prefix string
+		seq    uint64
+		limit  int
+		want   string
+	}{
+		{"ui/", 0, 0, "ui/a3 ui/c5 ui/b7 "},
+		{"ui/", 3, 0, "ui/c5 ui/b7 "},
+		{"ui/", 0, 2, "ui/a3 ui/c5 "},
+		{"u", 0, 2, "ui/a3 u4 "},
+		{"", 3, 1, "u4 "},
+		{"u", 4, 0, "ui/c5 uj6 ui/b7 "},
+		{"", 5, 0, "uj6 ui/b7 "},
+		{"z", 0, 0, ""},
+	}
+	for _, tt := range tests {
+		entries, err := b.PrefixAfter([]byte(tt.prefix), tt.seq, tt.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var s string
+		for _, e := range entries {
+			if string(e.Data) != string(e.Key) {
+				t.Fatal(e)
+			}
+			s += fmt.Sprintf("%s%d ", e.Key, e.Seq)
+		}
+		if s != tt.want {
+			t.Errorf("PrefixAfter(%q, %d, %d) = %q, want %q", tt.prefix, tt.seq, tt.limit, s, tt.want)
+		}
+	}
+}
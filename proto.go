@@ -0,0 +1,144 @@
+package boltseq
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// ErrInvalidProto is returned when decoding malformed protobuf messages.
+var ErrInvalidProto = errors.New("invalid protobuf message")
+
+// ChangeOp is an operation of a change record.
+type ChangeOp int
+
+const (
+	ChangePut ChangeOp = iota
+	ChangeDelete
+)
+
+// Change is a record of an operation on a bucket. Entry holds sequence
+// number and key of deleted items, without data.
+type Change struct {
+	Op    ChangeOp
+	Entry Entry
+}
+
+// protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+func appendVarint(p []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(p, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func appendTag(p []byte, field int, wire int) []byte {
+	return appendVarint(p, uint64(field<<3|wire))
+}
+
+func appendBytesField(p []byte, field int, b []byte) []byte {
+	if len(b) == 0 {
+		return p
+	}
+	p = appendTag(p, field, wireBytes)
+	p = appendVarint(p, uint64(len(b)))
+	return append(p, b...)
+}
+
+func appendVarintField(p []byte, field int, v uint64) []byte {
+	if v == 0 {
+		return p
+	}
+	return appendVarint(appendTag(p, field, wireVarint), v)
+}
+
+// parseProto calls fn for every field of message p. Value of varint fields
+// is passed in v, of length-delimited fields in b. Other fields are skipped.
+func parseProto(p []byte, fn func(field int, v uint64, b []byte) error) error {
+	for len(p) > 0 {
+		tag, n := binary.Uvarint(p)
+		if n <= 0 {
+			return ErrInvalidProto
+		}
+		p = p[n:]
+		field, wire := int(tag>>3), int(tag&7)
+
+		var v uint64
+		var b []byte
+		switch wire {
+		case wireVarint:
+			if v, n = binary.Uvarint(p); n <= 0 {
+				return ErrInvalidProto
+			}
+		case wireFixed64:
+			n = 8
+		case wireFixed32:
+			n = 4
+		case wireBytes:
+			size, m := binary.Uvarint(p)
+			if m <= 0 || uint64(len(p)-m) < size {
+				return ErrInvalidProto
+			}
+			b, n = p[m:m+int(size)], m+int(size)
+		default:
+			return ErrInvalidProto
+		}
+		if len(p) < n {
+			return ErrInvalidProto
+		}
+		p = p[n:]
+
+		if err := fn(field, v, b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MarshalProto encodes entry as protobuf Entry message.
+func (e Entry) MarshalProto() []byte {
+	p := appendVarintField(nil, 1, e.Seq)
+	p = appendBytesField(p, 2, e.Key)
+	return appendBytesField(p, 3, e.Data)
+}
+
+// UnmarshalProto decodes protobuf Entry message. Unknown fields are ignored.
+// Key and data are copied.
+func (e *Entry) UnmarshalProto(p []byte) error {
+	*e = Entry{}
+	return parseProto(p, func(field int, v uint64, b []byte) error {
+		switch field {
+		case 1:
+			e.Seq = v
+		case 2:
+			e.Key = append([]byte{}, b...)
+		case 3:
+			e.Data = append([]byte{}, b...)
+		}
+		return nil
+	})
+}
+
+// MarshalProto encodes change as protobuf Change message.
+func (c Change) MarshalProto() []byte {
+	p := appendVarintField(nil, 1, uint64(c.Op))
+	return appendBytesField(p, 2, c.Entry.MarshalProto())
+}
+
+// UnmarshalProto decodes protobuf Change message. Unknown fields are ignored.
+func (c *Change) UnmarshalProto(p []byte) error {
+	*c = Change{}
+	return parseProto(p, func(field int, v uint64, b []byte) error {
+		switch field {
+		case 1:
+			c.Op = ChangeOp(v)
+		case 2:
+			return c.Entry.UnmarshalProto(b)
+		}
+		return nil
+	})
+}
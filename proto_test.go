@@ -0,0 +1,37 @@
+package boltseq
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestEntry_proto(t *testing.T) {
+	e := Entry{Seq: 1, Key: []byte("k"), Data: []byte("d")}
+	p := e.MarshalProto()
+	if exp := []byte{0x08, 1, 0x12, 1, 'k', 0x1a, 1, 'd'}; !bytes.Equal(p, exp) {
+		t.Fatalf("%x", p)
+	}
+
+	// Unknown fields are skipped
+	p = append(p, 0x78, 5, 0x25, 1, 2, 3, 4, 0x29, 1, 2, 3, 4, 5, 6, 7, 8)
+	var u Entry
+	if err := u.UnmarshalProto(p); err != nil || !reflect.DeepEqual(u, e) {
+		t.Fatal(u, err)
+	}
+	if err := u.UnmarshalProto(p[:4]); err != ErrInvalidProto {
+		t.Fatal(err)
+	}
+}
+
+func TestChange_proto(t *testing.T) {
+	c := Change{Op: ChangeDelete, Entry: Entry{Seq: 300, Key: []byte("key")}}
+	var u Change
+	if err := u.UnmarshalProto(c.MarshalProto()); err != nil || !reflect.DeepEqual(u, c) {
+		t.Fatal(u, err)
+	}
+
+	if p := (Change{}).MarshalProto(); len(p) != 0 {
+		t.Fatal(p)
+	}
+}
@@ -0,0 +1,178 @@
+package boltseq
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrQuotaExceeded is returned by Put if the bucket would exceed its quota.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// meta key holding total stored bytes
+var metaKeySize = []byte("size")
+
+// entrySize returns number of bytes taken by a stored key-value pair,
+// including chunks of its data.
+func entrySize(key []byte, v Value) int64 {
+	if v == nil {
+		return 0
+	}
+	size := int64(len(key) + len(v))
+	if h, n, ok := parseHeader(v); ok && h.flags&flagChunked != 0 {
+		_, chunks, _ := parseChunkRef(v[n:])
+		size += chunks
+	}
+	return size
+}
+
+// storedSize returns total stored bytes as tracked in the meta bucket.
+// Returns false if size is not being tracked.
+func (b *Bucket) storedSize() (int64, bool) {
+	bm := b.bucket(bucketNameMeta)
+	if bm == nil {
+		return 0, false
+	}
+	v := bm.Get(metaKeySize)
+	if len(v) != 8 {
+		return 0, false
+	}
+	return int64(binary.BigEndian.Uint64(v)), true
+}
+
+func (b *Bucket) setStoredSize(size int64) error {
+	bm, err := b.createBucket(bucketNameMeta)
+	if err != nil {
+		return err
+	}
+	v := make([]byte, 8)
+	binary.BigEndian.PutUint64(v, uint64(size))
+	return bm.Put(metaKeySize, v)
+}
+
+// Size returns total bytes of keys and values stored in the bucket, including
+// value headers and chunks, as counted by Quota. Called within read-write
+// transaction it starts tracking the size in the meta sub-bucket, after which
+// it's updated by every write instead of scanning the bucket.
+func (b *Bucket) Size() (int64, error) {
+	if size, ok := b.storedSize(); ok {
+		return size, nil
+	}
+	size, err := b.scanSize()
+	if err != nil {
+		return 0, err
+	}
+	if err := b.setStoredSize(size); err != nil && err != bolt.ErrTxNotWritable {
+		return 0, err
+	}
+	return size, nil
+}
+
+// trackSize returns total stored bytes, starting to track them if not done yet.
+func (b *Bucket) trackSize() (int64, error) {
+	if size, ok := b.storedSize(); ok {
+		return size, nil
+	}
+	size, err := b.scanSize()
+	if err != nil {
+		return 0, err
+	}
+	return size, b.setStoredSize(size)
+}
+
+// scanSize returns total stored bytes counted by iterating the data sub-bucket.
+func (b *Bucket) scanSize() (int64, error) {
+	var size int64
+	if bd := b.bucket(bucketNameData); bd != nil {
+		err := forEach(bd, func(k, v []byte) error {
+			size += entrySize(k, v)
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return size, nil
+}
+
+// growSize adds delta to total stored bytes, if they are being tracked.
+func (b *Bucket) growSize(delta int64) error {
+	size, ok := b.storedSize()
+	if !ok || delta == 0 {
+		return nil
+	}
+	return b.setStoredSize(size + delta)
+}
+
+// reserve makes sure a new value for the key of the given size fits within
+// the quota, evicting expired items and then the oldest ones if configured
+// to do so. Returns size of the value currently stored for the key.
+func (b *Bucket) reserve(key []byte, size int64) (int64, error) {
+	oldSize, err := b.storedEntrySize(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if b.Quota <= 0 {
+		return oldSize, nil
+	}
+	if size > b.Quota {
+		return 0, ErrQuotaExceeded
+	}
+
+	total, err := b.trackSize()
+	if err != nil {
+		return 0, err
+	}
+
+	if total-oldSize+size > b.Quota && b.QuotaEvict && b.hasTTL() {
+		if _, err := b.expire(0); err != nil {
+			return 0, err
+		}
+		// The key itself may have expired
+		if oldSize, err = b.storedEntrySize(key); err != nil {
+			return 0, err
+		}
+		if total, err = b.trackSize(); err != nil {
+			return 0, err
+		}
+	}
+
+	for total-oldSize+size > b.Quota {
+		if !b.QuotaEvict {
+			return 0, ErrQuotaExceeded
+		}
+
+		c := b.rawCursor()
+		ok := c.First()
+		if ok && bytes.Equal(c.Key(), key) {
+			ok = c.Next()
+		}
+		if !ok {
+			if err := c.Err(); err != nil {
+				return 0, err
+			}
+			return 0, ErrQuotaExceeded
+		}
+		if err := c.Delete(); err != nil {
+			return 0, err
+		}
+
+		if total, err = b.trackSize(); err != nil {
+			return 0, err
+		}
+	}
+
+	return oldSize, nil
+}
+
+// storedEntrySize returns number of bytes taken by the entry of the key.
+func (b *Bucket) storedEntrySize(key []byte) (int64, error) {
+	old := b.get(key)
+	if old != nil && !old.IsValid() {
+		return 0, ErrInvalidValue
+	}
+	return entrySize(b.storeKey(key), old), nil
+}
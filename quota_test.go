@@ -0,0 +1,147 @@
+package boltseq
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestBucket_quota(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		b := NewBucket(tx.Bucket(testBucketName))
+
+		// Existing data is accounted for when quota is enabled
+		if _, err := b.Put([]byte("a"), []byte("1")); err != nil {
+			t.Fatal(err)
+		}
+
+		// Every item takes 1+10+1 bytes
+		b.Quota = 36
+		for _, k := range []string{"b", "c"} {
+			if _, err := b.Put([]byte(k), []byte("1")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := b.Put([]byte("d"), []byte("1")); !errors.Is(err, ErrQuotaExceeded) {
+			t.Fatal(err)
+		}
+		// Overwriting with the same size fits
+		if _, err := b.Put([]byte("a"), []byte("2")); err != nil {
+			t.Fatal(err)
+		}
+
+		// Oldest item is "b" now
+		b.QuotaEvict = true
+		if _, err := b.Put([]byte("d"), []byte("1")); err != nil {
+			t.Fatal(err)
+		}
+		if b.Get([]byte("b")) != nil || b.Get([]byte("c")) == nil {
+			t.Fatal("b not evicted")
+		}
+
+		if err := b.Delete([]byte("c")); err != nil {
+			t.Fatal(err)
+		}
+		if size, ok := b.storedSize(); !ok || size != 24 {
+			t.Fatal(size, ok)
+		}
+
+		if _, err := b.Put([]byte("big"), make([]byte, 30)); !errors.Is(err, ErrQuotaExceeded) {
+			t.Fatal(err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBucket_size(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		b := NewBucket(tx.Bucket(testBucketName))
+		_, err := b.Put([]byte("a"), []byte("123"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Computed on demand in read-only transaction
+	err = db.View(func(tx *bolt.Tx) error {
+		b := NewBucket(tx.Bucket(testBucketName))
+		if size, err := b.Size(); size != 14 || err != nil {
+			t.Fatal(size, err)
+		}
+		if _, ok := b.storedSize(); ok {
+			t.Fatal("size tracked")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Tracked once called in read-write transaction
+	err = db.Update(func(tx *bolt.Tx) error {
+		b := NewBucket(tx.Bucket(testBucketName))
+		if size, err := b.Size(); size != 14 || err != nil {
+			t.Fatal(size, err)
+		}
+		if _, err := b.Put([]byte("b"), []byte("1")); err != nil {
+			return err
+		}
+		if err := b.Delete([]byte("a")); err != nil {
+			return err
+		}
+		if size, ok := b.storedSize(); size != 12 || !ok {
+			t.Fatal(size, ok)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBucket_quotaRename(t *testing.T) {
+	b := NewMemBucket()
+	b.Quota = 36
+	for _, k := range []string{"a", "b", "c"} {
+		if _, err := b.Put([]byte(k), []byte("1")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := b.Rename([]byte("a"), []byte("aa")); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatal(err)
+	}
+	if err := b.Rename([]byte("a"), []byte("z")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Renamed item is the oldest, but it's not evicted
+	b.QuotaEvict = true
+	if err := b.Rename([]byte("z"), []byte("zz")); err != nil {
+		t.Fatal(err)
+	}
+	if s := orderOf(t, b); s != "zz1 c3 " {
+		t.Fatal(s)
+	}
+	if size, err := b.Size(); err != nil || size != 25 {
+		t.Fatal(size, err)
+	}
+}
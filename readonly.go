@@ -0,0 +1,203 @@
+package boltseq
+
+import (
+	"context"
+	"io"
+)
+
+// ReadOnlyBucket gives read access to a boltseq bucket, without any methods
+// allowing for modification.
+type ReadOnlyBucket struct {
+	b *Bucket
+}
+
+// NewReadOnlyBucket creates a read-only boltseq bucket at given location.
+func NewReadOnlyBucket(loc Location) *ReadOnlyBucket {
+	return NewBucket(loc).ReadOnly()
+}
+
+// ReadOnly returns read-only view of the bucket, sharing its options.
+func (b *Bucket) ReadOnly() *ReadOnlyBucket {
+	return &ReadOnlyBucket{b: b}
+}
+
+// Get returns Value for the key. See Bucket.Get.
+func (r *ReadOnlyBucket) Get(key []byte) Value {
+	return r.b.Get(key)
+}
+
+// GetCtx returns Value for the key. See Bucket.GetCtx.
+func (r *ReadOnlyBucket) GetCtx(ctx context.Context, key []byte) Value {
+	return r.b.GetCtx(ctx, key)
+}
+
+// GetSeq returns key with sequence number `seq`. See Bucket.GetSeq.
+func (r *ReadOnlyBucket) GetSeq(seq uint64) []byte {
+	return r.b.GetSeq(seq)
+}
+
+// First returns the item with the lowest sequence number. See Bucket.First.
+func (r *ReadOnlyBucket) First() (Entry, bool, error) {
+	return r.b.First()
+}
+
+// Last returns the item with the highest sequence number. See Bucket.Last.
+func (r *ReadOnlyBucket) Last() (Entry, bool, error) {
+	return r.b.Last()
+}
+
+// GetSeqEntry returns key and data for sequence number. See Bucket.GetSeqEntry.
+func (r *ReadOnlyBucket) GetSeqEntry(seq uint64) (key, data []byte, err error) {
+	return r.b.GetSeqEntry(seq)
+}
+
+// ForEach iterates over the bucket. See Bucket.ForEach.
+func (r *ReadOnlyBucket) ForEach(fn func(seq uint64, key, data []byte) error) error {
+	return r.b.ForEach(fn)
+}
+
+// ForEachFilter iterates over matching items. See Bucket.ForEachFilter.
+func (r *ReadOnlyBucket) ForEachFilter(f *Filter, fn func(seq uint64, key, data []byte) error) error {
+	return r.b.ForEachFilter(f, fn)
+}
+
+// ForEachCtx iterates over the bucket. See Bucket.ForEachCtx.
+func (r *ReadOnlyBucket) ForEachCtx(ctx context.Context, fn func(ctx context.Context, seq uint64, key, data []byte) error) error {
+	return r.b.ForEachCtx(ctx, fn)
+}
+
+// ForEachReverse iterates over the bucket newest first. See Bucket.ForEachReverse.
+func (r *ReadOnlyBucket) ForEachReverse(fn func(seq uint64, key, data []byte) error) error {
+	return r.b.ForEachReverse(fn)
+}
+
+// Range iterates over items within a range. See Bucket.Range.
+func (r *ReadOnlyBucket) Range(from, to uint64, fn func(seq uint64, key, data []byte) error) error {
+	return r.b.Range(from, to, fn)
+}
+
+// ReadRange returns copies of items within a range. See Bucket.ReadRange.
+func (r *ReadOnlyBucket) ReadRange(minSeq, maxSeq uint64, limit int) ([]Entry, error) {
+	return r.b.ReadRange(minSeq, maxSeq, limit)
+}
+
+// PrefixAfter returns copies of items with key prefix after seq. See Bucket.PrefixAfter.
+func (r *ReadOnlyBucket) PrefixAfter(prefix []byte, seq uint64, limit int) ([]Entry, error) {
+	return r.b.PrefixAfter(prefix, seq, limit)
+}
+
+// Newest returns the newest item among keys. See Bucket.Newest.
+func (r *ReadOnlyBucket) Newest(keys ...[]byte) (Entry, bool, error) {
+	return r.b.Newest(keys...)
+}
+
+// Oldest returns the oldest item among keys. See Bucket.Oldest.
+func (r *ReadOnlyBucket) Oldest(keys ...[]byte) (Entry, bool, error) {
+	return r.b.Oldest(keys...)
+}
+
+// CountRange returns number of items within a range. See Bucket.CountRange.
+func (r *ReadOnlyBucket) CountRange(minSeq, maxSeq uint64) (int, error) {
+	return r.b.CountRange(minSeq, maxSeq)
+}
+
+// BytesRange returns stored bytes of items within a range. See Bucket.BytesRange.
+func (r *ReadOnlyBucket) BytesRange(minSeq, maxSeq uint64) (int64, error) {
+	return r.b.BytesRange(minSeq, maxSeq)
+}
+
+// PageByKey returns copies of items in key order. See Bucket.PageByKey.
+func (r *ReadOnlyBucket) PageByKey(afterKey []byte, limit int) ([]Entry, error) {
+	return r.b.PageByKey(afterKey, limit)
+}
+
+// Log returns iterator over the change log. See Bucket.Log.
+func (r *ReadOnlyBucket) Log() *LogCursor {
+	return r.b.Log()
+}
+
+// GetAsOf returns Value the key had as of seq. See Bucket.GetAsOf.
+func (r *ReadOnlyBucket) GetAsOf(key []byte, seq uint64) (Value, error) {
+	return r.b.GetAsOf(key, seq)
+}
+
+// AsOf returns view of the bucket as of seq. See Bucket.AsOf.
+func (r *ReadOnlyBucket) AsOf(seq uint64) *ReadOnlyView {
+	return r.b.AsOf(seq)
+}
+
+// Export writes selected items to w. See Bucket.Export.
+func (r *ReadOnlyBucket) Export(w io.Writer, opts ExportOptions) (int, error) {
+	return r.b.Export(w, opts)
+}
+
+// Backward returns reverse iterator over the bucket. See Bucket.Backward.
+func (r *ReadOnlyBucket) Backward() *ReverseCursor {
+	return r.b.Backward()
+}
+
+// Cursor returns read-only iterator over the bucket.
+func (r *ReadOnlyBucket) Cursor() *ReadOnlyCursor {
+	return &ReadOnlyCursor{c: r.b.Cursor()}
+}
+
+// CursorOpts returns read-only iterator over the bucket configured by opts.
+func (r *ReadOnlyBucket) CursorOpts(opts CursorOptions) *ReadOnlyCursor {
+	return &ReadOnlyCursor{c: r.b.CursorOpts(opts)}
+}
+
+// ReadOnlyCursor is a Cursor without methods allowing for modification.
+type ReadOnlyCursor struct {
+	c *Cursor
+}
+
+// First moves cursor to the first key/value pair. See Cursor.First.
+func (r *ReadOnlyCursor) First() bool { return r.c.First() }
+
+// Last moves cursor to the last key/value pair. See Cursor.Last.
+func (r *ReadOnlyCursor) Last() bool { return r.c.Last() }
+
+// Next moves cursor to the next key/value pair. See Cursor.Next.
+func (r *ReadOnlyCursor) Next() bool { return r.c.Next() }
+
+// Prev moves cursor to the previous key/value pair. See Cursor.Prev.
+func (r *ReadOnlyCursor) Prev() bool { return r.c.Prev() }
+
+// Seek moves cursor to the given seq number. See Cursor.Seek.
+func (r *ReadOnlyCursor) Seek(seq uint64) bool { return r.c.Seek(seq) }
+
+// SeekKey moves cursor to the given key. See Cursor.SeekKey.
+func (r *ReadOnlyCursor) SeekKey(key []byte) bool { return r.c.SeekKey(key) }
+
+// SetFilter makes the cursor skip items not matching f. See Cursor.SetFilter.
+func (r *ReadOnlyCursor) SetFilter(f *Filter) { r.c.SetFilter(f) }
+
+// Err returns error, if any.
+func (r *ReadOnlyCursor) Err() error { return r.c.Err() }
+
+// Seq returns current sequence number.
+func (r *ReadOnlyCursor) Seq() uint64 { return r.c.Seq() }
+
+// Key returns current key.
+func (r *ReadOnlyCursor) Key() []byte { return r.c.Key() }
+
+// Data returns current data for the key.
+func (r *ReadOnlyCursor) Data() ([]byte, error) { return r.c.Data() }
+
+// LoadData returns current data, failing for expired items. See Cursor.LoadData.
+func (r *ReadOnlyCursor) LoadData() ([]byte, error) { return r.c.LoadData() }
+
+// Entry returns the current item. See Cursor.Entry.
+func (r *ReadOnlyCursor) Entry() (Entry, error) { return r.c.Entry() }
+
+// Flags returns application flags of the current item.
+func (r *ReadOnlyCursor) Flags() byte { return r.c.Flags() }
+
+// Origin returns origin metadata of the current item. See Cursor.Origin.
+func (r *ReadOnlyCursor) Origin() []byte { return r.c.Origin() }
+
+// ResumeToken returns token for continuing iteration. See Cursor.ResumeToken.
+func (r *ReadOnlyCursor) ResumeToken() (string, error) { return r.c.ResumeToken() }
+
+// SeekToken moves cursor to position encoded in token t. See Cursor.SeekToken.
+func (r *ReadOnlyCursor) SeekToken(t string) (bool, error) { return r.c.SeekToken(t) }
@@ -0,0 +1,42 @@
+package boltseq
+
+import (
+	"os"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestReadOnlyBucket(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := NewBucket(tx.Bucket(testBucketName)).Put([]byte("x"), []byte("v"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		b := NewReadOnlyBucket(tx.Bucket(testBucketName))
+		if v := b.Get([]byte("x")); string(v.Data()) != "v" {
+			t.Fatal(v)
+		}
+		if k := b.GetSeq(1); string(k) != "x" {
+			t.Fatal(k)
+		}
+		c := b.Cursor()
+		if !c.Last() || c.Seq() != 1 || string(c.Key()) != "x" {
+			t.Fatal(c.Seq(), c.Key())
+		}
+		return c.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
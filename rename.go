@@ -0,0 +1,97 @@
+package boltseq
+
+import "errors"
+
+// ErrKeyExists is returned when an operation requires a key not to exist.
+var ErrKeyExists = errors.New("key already exists")
+
+// Rename changes key of an item, keeping its sequence number and data.
+// Returns ErrKeyNotFound if oldKey doesn't exist and ErrKeyExists if newKey does.
+// Expired items count as absent; expired newKey is deleted, as by ExpireNow.
+func (b *Bucket) Rename(oldKey, newKey []byte) error {
+	return opError("rename", oldKey, 0, b.rename(oldKey, newKey))
+}
+
+func (b *Bucket) rename(oldKey, newKey []byte) error {
+	if b.AppendOnly {
+		return ErrAppendOnly
+	}
+	v := b.getLive(oldKey)
+	if v == nil {
+		return ErrKeyNotFound
+	}
+	if !v.IsValid() {
+		return ErrInvalidValue
+	}
+	if err := b.checkKey(newKey); err != nil {
+		return err
+	}
+	expired := false
+	if nv := b.get(newKey); nv != nil {
+		if !b.expired(nv) {
+			return ErrKeyExists
+		}
+		expired = true
+	}
+	dv, err := b.decode(b.storeKey(oldKey), v)
+	if err != nil {
+		return err
+	}
+	if err := b.Limits.check(newKey, dv.Data()); err != nil {
+		return err
+	}
+
+	if expired {
+		if err := b.delete(newKey); err != nil {
+			return err
+		}
+	}
+
+	// Make room for the new key, replacing the entry of the old one
+	sold, snew := b.storeKey(oldKey), b.storeKey(newKey)
+	if _, err := b.reserve(oldKey, entrySize(snew, v)); err != nil {
+		return err
+	}
+	// Eviction may have invalidated the value
+	v = b.get(oldKey)
+	if dv, err = b.decode(sold, v); err != nil {
+		return err
+	}
+
+	// Copy value, as it's not valid after deletion
+	nv, err := b.rebind(v, sold, snew, v.Seq())
+	if err != nil {
+		return err
+	}
+
+	if err := b.bucket(bucketNameSeq).Put(nv.seqBytes(), snew); err != nil {
+		return err
+	}
+	bd := b.bucket(bucketNameData)
+	if err := bd.Delete(sold); err != nil {
+		return err
+	}
+	if err := b.deleteKey(sold); err != nil {
+		return err
+	}
+	if err := bd.Put(snew, nv); err != nil {
+		return err
+	}
+	if err := b.putKey(snew, newKey); err != nil {
+		return err
+	}
+	if err := b.growSize(int64(len(snew) - len(sold))); err != nil {
+		return err
+	}
+	if err := b.verify([][]byte{oldKey, newKey}, nv.Seq()); err != nil {
+		return err
+	}
+	if b.ChangeLog {
+		old := b.logData(nv.Seq(), dv.Data())
+		if err := b.logChange(ChangeDelete, nv.Seq(), oldKey, old, nil, nil); err != nil {
+			return err
+		}
+		return b.logChange(ChangePut, nv.Seq(), newKey, nil, old.hash, nv.Origin())
+	}
+	return nil
+}
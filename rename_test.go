@@ -0,0 +1,63 @@
+package boltseq
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBucket_rename(t *testing.T) {
+	b := NewMemBucket()
+	for _, k := range []string{"a", "b", "c"} {
+		if _, err := b.Put([]byte(k), []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := b.Rename([]byte("a"), []byte("z")); err != nil {
+		t.Fatal(err)
+	}
+	if s := orderOf(t, b); s != "z1 b2 c3 " {
+		t.Fatal(s)
+	}
+	if v := b.Get([]byte("z")); string(v.Data()) != "a" || b.Get([]byte("a")) != nil {
+		t.Fatal(v)
+	}
+
+	if err := b.Rename([]byte("a"), []byte("y")); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatal(err)
+	}
+	if err := b.Rename([]byte("b"), []byte("c")); !errors.Is(err, ErrKeyExists) {
+		t.Fatal(err)
+	}
+}
+
+func TestBucket_renameOntoExpired(t *testing.T) {
+	now := time.Unix(1000, 0)
+	b := NewMemBucket()
+	b.Now = func() time.Time { return now }
+	b.Paranoid = true
+	if _, err := b.PutTTL([]byte("x"), []byte("old"), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Put([]byte("a"), []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Rename([]byte("a"), []byte("x")); !errors.Is(err, ErrKeyExists) {
+		t.Fatal(err)
+	}
+
+	now = now.Add(time.Hour)
+	if err := b.Rename([]byte("a"), []byte("x")); err != nil {
+		t.Fatal(err)
+	}
+	if v := b.Get([]byte("x")); string(v.Data()) != "new" || v.Seq() != 2 {
+		t.Fatal(v)
+	}
+	if k := b.GetSeq(1); k != nil {
+		t.Fatal(k)
+	}
+	if s := orderOf(t, b); s != "x2 " {
+		t.Fatal(s)
+	}
+}
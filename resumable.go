@@ -0,0 +1,146 @@
+package boltseq
+
+import "encoding/binary"
+
+// ResumableCursor iterates a bucket in sequence order across transactions.
+// It keeps a watermark, the highest sequence number visited, and continues
+// after it when attached to a bucket in a new transaction with Attach.
+//
+// Overwritten items get new sequence numbers, so items overwritten after
+// being visited are visited again. To avoid that, keys of recently visited
+// items are kept in a window of the given size and items with these keys are
+// skipped. With window at least the number of visited items, every key is
+// visited once.
+type ResumableCursor struct {
+	seq    uint64
+	window int
+	seen   map[string]bool
+	keys   []string // keys in the window, oldest first
+
+	c     *Cursor
+	moved bool
+}
+
+// NewResumableCursor returns cursor visiting items with sequence numbers
+// greater than after, skipping keys among window most recently visited.
+// It needs to be attached to a bucket with Attach before use.
+func NewResumableCursor(after uint64, window int) *ResumableCursor {
+	rc := &ResumableCursor{seq: after, window: window}
+	if window > 0 {
+		rc.seen = make(map[string]bool)
+	}
+	return rc
+}
+
+// Attach makes the cursor iterate b, continuing after the watermark. Call it
+// for every new transaction the iteration continues in.
+func (rc *ResumableCursor) Attach(b *Bucket) {
+	rc.c, rc.moved = b.Cursor(), false
+}
+
+// Next moves cursor to the next item not visited yet.
+// Returns false if there is no such item.
+func (rc *ResumableCursor) Next() bool {
+	if rc.c == nil {
+		return false
+	}
+	for {
+		var ok bool
+		if rc.moved {
+			ok = rc.c.Next()
+		} else {
+			ok, rc.moved = rc.c.Seek(rc.seq+1), true
+		}
+		if !ok {
+			return false
+		}
+		rc.seq = rc.c.Seq()
+		if rc.seen[string(rc.c.Key())] {
+			continue
+		}
+		rc.remember(rc.c.Key())
+		return true
+	}
+}
+
+// remember adds key to the window, dropping the oldest key if it's full.
+func (rc *ResumableCursor) remember(key []byte) {
+	if rc.window <= 0 {
+		return
+	}
+	if len(rc.keys) == rc.window {
+		delete(rc.seen, rc.keys[0])
+		rc.keys = rc.keys[1:]
+	}
+	rc.seen[string(key)] = true
+	rc.keys = append(rc.keys, string(key))
+}
+
+// Position returns the watermark, sequence number of the last visited item.
+func (rc *ResumableCursor) Position() uint64 { return rc.seq }
+
+// Err returns error, if any.
+func (rc *ResumableCursor) Err() error {
+	if rc.c == nil {
+		return nil
+	}
+	return rc.c.Err()
+}
+
+// Seq returns current sequence number.
+func (rc *ResumableCursor) Seq() uint64 { return rc.c.Seq() }
+
+// Key returns current key.
+func (rc *ResumableCursor) Key() []byte { return rc.c.Key() }
+
+// Data returns current data for the key.
+func (rc *ResumableCursor) Data() ([]byte, error) { return rc.c.Data() }
+
+// Entry returns the current item. See Cursor.Entry.
+func (rc *ResumableCursor) Entry() (Entry, error) { return rc.c.Entry() }
+
+// MarshalBinary encodes the watermark and the window, so iteration can be
+// resumed by another process.
+func (rc *ResumableCursor) MarshalBinary() ([]byte, error) {
+	p := make([]byte, 0, 3*binary.MaxVarintLen64)
+	var buf [binary.MaxVarintLen64]byte
+	for _, n := range []uint64{rc.seq, uint64(rc.window), uint64(len(rc.keys))} {
+		p = append(p, buf[:binary.PutUvarint(buf[:], n)]...)
+	}
+	for _, k := range rc.keys {
+		p = append(p, buf[:binary.PutUvarint(buf[:], uint64(len(k)))]...)
+		p = append(p, k...)
+	}
+	return p, nil
+}
+
+// UnmarshalBinary decodes state encoded by MarshalBinary. The cursor needs
+// to be attached again.
+func (rc *ResumableCursor) UnmarshalBinary(p []byte) error {
+	var vals [3]uint64
+	for n := range vals {
+		v, m := binary.Uvarint(p)
+		if m <= 0 {
+			return ErrInvalidValue
+		}
+		vals[n], p = v, p[m:]
+	}
+	if vals[2] > vals[1] {
+		return ErrInvalidValue
+	}
+
+	nrc := NewResumableCursor(vals[0], int(vals[1]))
+	for n := uint64(0); n < vals[2]; n++ {
+		size, m := binary.Uvarint(p)
+		if m <= 0 || uint64(len(p)-m) < size {
+			return ErrInvalidValue
+		}
+		nrc.remember(p[m : m+int(size)])
+		p = p[m+int(size):]
+	}
+	if len(p) != 0 {
+		return ErrInvalidValue
+	}
+	*rc = *nrc
+	return nil
+}
@@ -0,0 +1,84 @@
+package boltseq
+
+import (
+	"errors"
+	"testing"
+)
+
+func visit(t *testing.T, rc *ResumableCursor, n int) string {
+	var s string
+	for ; n != 0 && rc.Next(); n-- {
+		s += string(rc.Key())
+	}
+	if err := rc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestResumableCursor(t *testing.T) {
+	for _, tc := range []struct {
+		window int
+		want   string
+	}{
+		{0, "dac"},
+		{10, "dc"},
+		{1, "dac"}, // a dropped from the window by b
+	} {
+		b := NewMemBucket()
+		for _, k := range []string{"a", "b", "c", "d"} {
+			if _, err := b.Put([]byte(k), []byte(k)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		rc := NewResumableCursor(0, tc.window)
+		rc.Attach(b)
+		if got := visit(t, rc, 2); got != "ab" || rc.Position() != 2 {
+			t.Fatal(got, rc.Position())
+		}
+
+		// Overwrite visited and not yet visited keys between transactions
+		for _, k := range []string{"a", "c"} {
+			if _, err := b.Put([]byte(k), []byte(k)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		rc.Attach(b)
+		if got := visit(t, rc, -1); got != tc.want || rc.Position() != 6 {
+			t.Fatal(tc.window, got, rc.Position())
+		}
+	}
+}
+
+func TestResumableCursor_marshal(t *testing.T) {
+	b := NewMemBucket()
+	for _, k := range []string{"a", "b", "c"} {
+		if _, err := b.Put([]byte(k), []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rc := NewResumableCursor(0, 3)
+	rc.Attach(b)
+	visit(t, rc, 2)
+	p, err := rc.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := b.Put([]byte("a"), nil); err != nil {
+		t.Fatal(err)
+	}
+	var rc2 ResumableCursor
+	if err := rc2.UnmarshalBinary(p); err != nil {
+		t.Fatal(err)
+	}
+	rc2.Attach(b)
+	if got := visit(t, &rc2, -1); got != "c" || rc2.Position() != 4 {
+		t.Fatal(got, rc2.Position())
+	}
+
+	if err := rc2.UnmarshalBinary(p[:len(p)-1]); !errors.Is(err, ErrInvalidValue) {
+		t.Fatal(err)
+	}
+}
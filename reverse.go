@@ -0,0 +1,64 @@
+package boltseq
+
+// ForEachReverse calls fn for every item in the bucket in reverse order of
+// sequence numbers, newest first. Iteration stops on the first error returned by fn.
+func (b *Bucket) ForEachReverse(fn func(seq uint64, key, data []byte) error) error {
+	c := b.Cursor()
+	return b.forEach(c, c.Last, c.Prev, fn)
+}
+
+// Range calls fn for items with sequence numbers between from and to,
+// inclusive. Items are visited in ascending order if from <= to, and in
+// descending order otherwise. Iteration stops on the first error returned by fn.
+func (b *Bucket) Range(from, to uint64, fn func(seq uint64, key, data []byte) error) error {
+	if from <= to {
+		c := b.Cursor()
+		first := func() bool { return c.Seek(from) && c.Seq() <= to }
+		next := func() bool { return c.Next() && c.Seq() <= to }
+		return b.forEach(c, first, next, fn)
+	}
+
+	r := b.Backward()
+	first := func() bool { return r.Seek(from) && r.Seq() >= to }
+	next := func() bool { return r.Next() && r.Seq() >= to }
+	return b.forEach(r.c, first, next, fn)
+}
+
+// Backward returns iterator over the bucket in reverse order of sequence numbers.
+func (b *Bucket) Backward() *ReverseCursor {
+	return &ReverseCursor{c: b.Cursor()}
+}
+
+// ReverseCursor iterates items in reverse order of sequence numbers,
+// starting from the newest one.
+type ReverseCursor struct {
+	c *Cursor
+}
+
+// First moves cursor to the item with the highest sequence number.
+// Returns false on empty bucket, true otherwise.
+func (r *ReverseCursor) First() bool { return r.c.Last() }
+
+// Next moves cursor to the item with the next lower sequence number.
+// Returns false if reached beginning of the bucket, true otherwise.
+func (r *ReverseCursor) Next() bool { return r.c.Prev() }
+
+// Seek moves cursor to the item with the given sequence number or, if it
+// doesn't exist, to the nearest item with a lower one.
+// Returns false if no item, true otherwise.
+func (r *ReverseCursor) Seek(seq uint64) bool { return r.c.seekBack(seq) }
+
+// Err returns error, if any.
+func (r *ReverseCursor) Err() error { return r.c.Err() }
+
+// Seq returns current sequence number.
+func (r *ReverseCursor) Seq() uint64 { return r.c.Seq() }
+
+// Key returns current key.
+func (r *ReverseCursor) Key() []byte { return r.c.Key() }
+
+// Data returns current data for the key.
+func (r *ReverseCursor) Data() ([]byte, error) { return r.c.Data() }
+
+// Entry returns the current item. See Cursor.Entry.
+func (r *ReverseCursor) Entry() (Entry, error) { return r.c.Entry() }
@@ -0,0 +1,74 @@
+package boltseq
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBucket_reverse(t *testing.T) {
+	b := NewMemBucket()
+	for _, k := range []string{"a", "b", "c", "d", "e"} {
+		if _, err := b.Put([]byte(k), []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := b.DeleteSeq(3); err != nil {
+		t.Fatal(err)
+	}
+
+	collect := func(iter func(fn func(seq uint64, key, data []byte) error) error) string {
+		var s string
+		err := iter(func(seq uint64, key, data []byte) error {
+			s += fmt.Sprintf("%s%d ", key, seq)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	rangeOf := func(from, to uint64) string {
+		return collect(func(fn func(seq uint64, key, data []byte) error) error {
+			return b.Range(from, to, fn)
+		})
+	}
+
+	if s := collect(b.ForEachReverse); s != "e5 d4 b2 a1 " {
+		t.Fatal(s)
+	}
+
+	tests := []struct {
+		from, to uint64
+		want     string
+	}{
+		{1, 5, "a1 b2 d4 e5 "},
+		{2, 3, "b2 "},
+		{3, 3, ""},
+		{0, 100, "a1 b2 d4 e5 "},
+		{5, 1, "e5 d4 b2 a1 "},
+		{3, 2, "b2 "},
+		{100, 4, "e5 d4 "},
+		{3, 0, "b2 a1 "},
+		{100, 6, ""},
+	}
+	for _, tt := range tests {
+		if s := rangeOf(tt.from, tt.to); s != tt.want {
+			t.Errorf("Range(%d, %d) = %q, want %q", tt.from, tt.to, s, tt.want)
+		}
+	}
+
+	r := b.Backward()
+	var s string
+	for ok := r.First(); ok; ok = r.Next() {
+		s += string(r.Key())
+	}
+	if s != "edba" || r.Err() != nil {
+		t.Fatal(s, r.Err())
+	}
+	if !r.Seek(3) || r.Seq() != 2 {
+		t.Fatal(r.Seq())
+	}
+	if r.Seek(0) {
+		t.Fatal(r.Seq())
+	}
+}
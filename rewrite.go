@@ -0,0 +1,201 @@
+package boltseq
+
+import (
+	"os"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// DefaultRewriteBatch is the default number of entries copied per transaction
+// by RewriteInto.
+const DefaultRewriteBatch = 1000
+
+// RewriteOptions configures RewriteInto and Rewrite.
+type RewriteOptions struct {
+	// BatchSize is the number of entries copied per transaction of the
+	// destination. Defaults to DefaultRewriteBatch.
+	BatchSize int
+
+	// Interval is the pause between transactions, limiting the rate of writes.
+	Interval time.Duration
+
+	// BoltOptions are used for opening the rewritten database by Rewrite.
+	BoltOptions *bolt.Options
+}
+
+// rewriter copies buckets into dst in batches of entries.
+type rewriter struct {
+	dst  *bolt.DB
+	opts RewriteOptions
+	tx   *bolt.Tx
+	n    int
+}
+
+// RewriteInto copies buckets at paths, including nested buckets and sequences
+// of sub-buckets, into dst. Entries are stored in pages filled up to 100%, so
+// the copy doesn't hold space freed by deleted items, which bbolt never gives
+// back. The source is read within a single read-only transaction, so the copy
+// is consistent, while dst is written in small transactions, see RewriteOptions.
+// Empty path copies all buckets.
+func (db *DB) RewriteInto(dst *bolt.DB, paths [][][]byte, opts RewriteOptions) error {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultRewriteBatch
+	}
+	w := &rewriter{dst: dst, opts: opts}
+	err := db.View(func(tx *bolt.Tx) error {
+		for _, path := range paths {
+			if len(path) == 0 {
+				err := tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+					return w.copy(b, [][]byte{name})
+				})
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			src, err := rawBucket(tx, path)
+			if err != nil {
+				return err
+			}
+			if err := w.copy(src, path); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if w.tx != nil {
+		if err != nil {
+			w.tx.Rollback()
+			return err
+		}
+		return w.tx.Commit()
+	}
+	return err
+}
+
+// rawBucket returns bolt bucket at path within tx.
+func rawBucket(tx *bolt.Tx, path [][]byte) (*bolt.Bucket, error) {
+	b := tx.Bucket(path[0])
+	for _, name := range path[1:] {
+		if b == nil {
+			break
+		}
+		b = b.Bucket(name)
+	}
+	if b == nil {
+		return nil, bolt.ErrBucketNotFound
+	}
+	return b, nil
+}
+
+// bucket returns bucket at path in the current transaction of dst, starting
+// a new one if the batch is full.
+func (w *rewriter) bucket(path [][]byte) (*bolt.Bucket, error) {
+	if w.tx != nil && w.n >= w.opts.BatchSize {
+		if err := w.tx.Commit(); err != nil {
+			w.tx = nil
+			return nil, err
+		}
+		w.tx, w.n = nil, 0
+		time.Sleep(w.opts.Interval)
+	}
+	if w.tx == nil {
+		tx, err := w.dst.Begin(true)
+		if err != nil {
+			return nil, err
+		}
+		w.tx = tx
+	}
+
+	b, err := w.tx.CreateBucketIfNotExists(path[0])
+	for _, name := range path[1:] {
+		if err != nil {
+			break
+		}
+		b, err = b.CreateBucketIfNotExists(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	b.FillPercent = 1
+	return b, nil
+}
+
+// copy copies entries and nested buckets of src into bucket at path.
+func (w *rewriter) copy(src *bolt.Bucket, path [][]byte) error {
+	b, err := w.bucket(path)
+	if err != nil {
+		return err
+	}
+	if err := b.SetSequence(src.Sequence()); err != nil {
+		return err
+	}
+
+	c := src.Cursor()
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if v == nil {
+			if err := w.copy(src.Bucket(k), append(path[:len(path):len(path)], k)); err != nil {
+				return err
+			}
+			continue
+		}
+		if b, err = w.bucket(path); err != nil {
+			return err
+		}
+		if err := b.Put(k, v); err != nil {
+			return err
+		}
+		w.n++
+	}
+	return nil
+}
+
+// Rewrite copies buckets at paths into a fresh file next to the database with
+// RewriteInto, then replaces the database file with it and reopens the
+// database. Buckets not listed are dropped.
+//
+// Rewrite requires exclusive access to db: no other goroutine may use it or
+// its buckets until Rewrite returns, as the underlying bolt.DB is closed and
+// replaced without synchronization, and writes made meanwhile would be lost.
+func (db *DB) Rewrite(paths [][][]byte, opts RewriteOptions) error {
+	path := db.Path()
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	// Leftover of an interrupted rewrite would be opened as is and merged
+	tmp := path + ".rewrite"
+	if err := os.Remove(tmp); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	dst, err := bolt.Open(tmp, fi.Mode(), nil)
+	if err != nil {
+		return err
+	}
+	err = db.RewriteInto(dst, paths, opts)
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := db.DB.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	// Rename is atomic, so the file holds either the old or the new database
+	err = os.Rename(tmp, path)
+	if err != nil {
+		os.Remove(tmp)
+	}
+	ndb, oerr := bolt.Open(path, fi.Mode(), opts.BoltOptions)
+	if oerr != nil {
+		return oerr
+	}
+	db.DB = ndb
+	db.notify()
+	return err
+}
@@ -0,0 +1,110 @@
+package boltseq
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestDB_rewrite(t *testing.T) {
+	bdb, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(bdb.Path())
+
+	db := NewDB(bdb)
+	db.Options.ChangeLog = true
+	path := [][]byte{testBucketName}
+	dropped := [][]byte{[]byte("dropped")}
+	data := bytes.Repeat([]byte("x"), 1000)
+	err = db.UpdateBuckets([][][]byte{path, dropped}, func(bs []*Bucket) error {
+		b := bs[0]
+		for n := 0; n < 2000; n++ {
+			if _, err := b.Put([]byte(fmt.Sprintf("k%04d", n)), data); err != nil {
+				return err
+			}
+		}
+		for n := 0; n < 1990; n++ {
+			if err := b.Delete([]byte(fmt.Sprintf("k%04d", n))); err != nil {
+				return err
+			}
+		}
+		if _, err := b.SubBucket([]byte("nested")).Put([]byte("a"), []byte("1")); err != nil {
+			return err
+		}
+		_, err := bs[1].Put([]byte("a"), []byte("1"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.Stat(db.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Leftover of an interrupted rewrite isn't merged
+	stale, err := bolt.Open(db.Path()+".rewrite", 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = stale.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucket([]byte("stale"))
+		return err
+	})
+	if cerr := stale.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Rewrite([][][]byte{path}, RewriteOptions{BatchSize: 7}); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	err = db.View(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte("stale")) != nil {
+			t.Fatal("stale bucket merged")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := os.Stat(db.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Size() >= before.Size() {
+		t.Fatal(before.Size(), after.Size())
+	}
+
+	err = db.UpdateBucket(path, func(b *Bucket) error {
+		if v := b.Get([]byte("k1999")); !bytes.Equal(v.Data(), data) || v.Seq() != 2000 {
+			t.Fatal(v.Seq())
+		}
+		if v := b.SubBucket([]byte("nested")).Get([]byte("a")); string(v.Data()) != "1" {
+			t.Fatal(v)
+		}
+		if n := len(logOf(t, b)); n == 0 {
+			t.Fatal("log not copied")
+		}
+		// Sequences are kept
+		seq, err := b.Put([]byte("new"), nil)
+		if err != nil || seq != 2001 {
+			t.Fatal(seq, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.ViewBucket(dropped, func(*Bucket) error { return nil }); err == nil {
+		t.Fatal("bucket not dropped")
+	}
+}
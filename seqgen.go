@@ -0,0 +1,60 @@
+package boltseq
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrSeqNotMonotonic is returned when SeqGenerator returns sequence number
+// not greater than the last one used in the bucket.
+var ErrSeqNotMonotonic = errors.New("sequence number not monotonic")
+
+// SeqGenerator provides sequence numbers for new items, e.g. from timestamps,
+// hybrid logical clocks or an allocator shared between buckets.
+type SeqGenerator interface {
+	// NextSeq returns a sequence number greater than last, which is the
+	// highest sequence number used in the bucket so far.
+	NextSeq(last uint64) (uint64, error)
+}
+
+// SeqGeneratorFunc adapts a function to SeqGenerator.
+type SeqGeneratorFunc func(last uint64) (uint64, error)
+
+// NextSeq calls f(last).
+func (f SeqGeneratorFunc) NextSeq(last uint64) (uint64, error) {
+	return f(last)
+}
+
+// TimeSeq generates sequence numbers from current Unix time in nanoseconds,
+// or last+1 if the clock didn't advance.
+var TimeSeq SeqGenerator = SeqGeneratorFunc(func(last uint64) (uint64, error) {
+	if now := uint64(time.Now().UnixNano()); now > last {
+		return now, nil
+	}
+	return last + 1, nil
+})
+
+// nextSeq returns sequence number for a new item in seq bucket bs.
+// Like requested ones, it must fit in 63 bits, see header.go.
+func (b *Bucket) nextSeq(bs KVBucket) (uint64, error) {
+	if b.SeqGenerator == nil {
+		seq, err := bs.NextSequence()
+		if err == nil && seq >= seqExtBit {
+			return 0, ErrInvalidSeq
+		}
+		return seq, err
+	}
+
+	last := bs.Sequence()
+	seq, err := b.SeqGenerator.NextSeq(last)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case seq <= last:
+		return 0, ErrSeqNotMonotonic
+	case seq >= seqExtBit:
+		return 0, ErrInvalidSeq
+	}
+	return seq, bs.SetSequence(seq)
+}
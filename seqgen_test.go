@@ -0,0 +1,47 @@
+package boltseq
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBucket_seqGenerator(t *testing.T) {
+	b := NewMemBucket()
+	b.SeqGenerator = SeqGeneratorFunc(func(last uint64) (uint64, error) {
+		return last + 10, nil
+	})
+
+	for n, k := range []string{"a", "b", "a"} {
+		seq, err := b.Put([]byte(k), nil)
+		if err != nil || seq != uint64(n+1)*10 {
+			t.Fatal(seq, err)
+		}
+	}
+	if s := orderOf(t, b); s != "b20 a30 " {
+		t.Fatal(s)
+	}
+
+	b.SeqGenerator = TimeSeq
+	seq, err := b.Put([]byte("c"), nil)
+	if err != nil || seq <= 30 {
+		t.Fatal(seq, err)
+	}
+
+	b.SeqGenerator = SeqGeneratorFunc(func(last uint64) (uint64, error) {
+		return last, nil
+	})
+	if _, err := b.Put([]byte("d"), nil); !errors.Is(err, ErrSeqNotMonotonic) {
+		t.Fatal(err)
+	}
+
+	// Generated numbers can't take the extended header bit
+	b.SeqGenerator = SeqGeneratorFunc(func(last uint64) (uint64, error) {
+		return seqExtBit + 5, nil
+	})
+	if _, err := b.Put([]byte("d"), nil); !errors.Is(err, ErrInvalidSeq) {
+		t.Fatal(err)
+	}
+	if b.Get([]byte("d")) != nil {
+		t.Fatal("stored")
+	}
+}
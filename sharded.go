@@ -0,0 +1,93 @@
+package boltseq
+
+import (
+	"hash/fnv"
+	"strconv"
+)
+
+// ShardedBucket spreads keys over a number of boltseq buckets by key hash,
+// while keeping a single sequence space, so items of all shards can be
+// iterated in global sequence order. Each shard is a bolt bucket named
+// "shard<N>" at the location; the number of shards must not change once
+// data is stored.
+type ShardedBucket struct {
+	loc    Location
+	shards []*Bucket
+}
+
+// NewShardedBucket creates a sharded bucket with n shards at given location.
+// Like NewBucket, this call has no side-effects.
+func NewShardedBucket(loc Location, n int) *ShardedBucket {
+	sb := &ShardedBucket{loc: loc, shards: make([]*Bucket, n)}
+	for i := range sb.shards {
+		sb.shards[i] = NewStoreBucket(nestedStore{parent: BoltStore(loc), name: []byte("shard" + strconv.Itoa(i))})
+	}
+	return sb
+}
+
+// SetOptions sets options for all shards.
+func (sb *ShardedBucket) SetOptions(opts Options) {
+	for _, b := range sb.shards {
+		b.Options = opts
+	}
+}
+
+// Shard returns bucket holding the key.
+func (sb *ShardedBucket) Shard(key []byte) *Bucket {
+	h := fnv.New32a()
+	h.Write(key)
+	return sb.shards[h.Sum32()%uint32(len(sb.shards))]
+}
+
+// Put adds key-value pair to its shard, giving it the next global sequence number.
+func (sb *ShardedBucket) Put(key []byte, value []byte) (uint64, error) {
+	bm, err := sb.loc.CreateBucketIfNotExists(bucketNameMeta)
+	if err != nil {
+		return 0, err
+	}
+	seq, err := bm.NextSequence()
+	if err != nil {
+		return 0, err
+	}
+	return sb.Shard(key).put(key, value, seq)
+}
+
+// Get returns Value for the key.
+func (sb *ShardedBucket) Get(key []byte) Value {
+	return sb.Shard(key).Get(key)
+}
+
+// GetSeq returns key with sequence number `seq`.
+func (sb *ShardedBucket) GetSeq(seq uint64) []byte {
+	for _, b := range sb.shards {
+		if k := b.GetSeq(seq); k != nil {
+			return k
+		}
+	}
+	return nil
+}
+
+// Delete deletes a key.
+func (sb *ShardedBucket) Delete(key []byte) error {
+	return sb.Shard(key).Delete(key)
+}
+
+// DeleteSeq deletes a key with sequence number `seq`.
+// Returns whether the sequence number was present.
+func (sb *ShardedBucket) DeleteSeq(seq uint64) (bool, error) {
+	for _, b := range sb.shards {
+		if deleted, err := b.DeleteSeq(seq); deleted || err != nil {
+			return deleted, err
+		}
+	}
+	return false, nil
+}
+
+// Cursor returns iterator over all shards in order of sequence numbers.
+func (sb *ShardedBucket) Cursor() *Cursor {
+	cs := make([]*Cursor, len(sb.shards))
+	for i, b := range sb.shards {
+		cs[i] = b.Cursor()
+	}
+	return MergeCursors(cs...)
+}
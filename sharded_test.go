@@ -0,0 +1,86 @@
+package boltseq
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestShardedBucket(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	const count = 100
+	err = db.Update(func(tx *bolt.Tx) error {
+		sb := NewShardedBucket(tx.Bucket(testBucketName), 4)
+		for n := 0; n < count; n++ {
+			seq, err := sb.Put([]byte(fmt.Sprint(n)), []byte(fmt.Sprint(n)))
+			if err != nil || seq != uint64(n+1) {
+				t.Fatal(seq, err)
+			}
+		}
+		if _, err := sb.Put([]byte("x"), nil); err != nil {
+			t.Fatal(err)
+		}
+		if deleted, err := sb.DeleteSeq(count + 1); !deleted || err != nil {
+			t.Fatal(deleted, err)
+		}
+		if deleted, err := sb.DeleteSeq(count + 1); deleted || err != nil {
+			t.Fatal(deleted, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.View(func(tx *bolt.Tx) error {
+		sb := NewShardedBucket(tx.Bucket(testBucketName), 4)
+		if k := sb.GetSeq(42); string(k) != "41" {
+			t.Fatal(k)
+		}
+		if v := sb.Get([]byte("7")); v.Seq() != 8 {
+			t.Fatal(v)
+		}
+
+		c := sb.Cursor()
+		n := 0
+		for ok := c.First(); ok; ok = c.Next() {
+			if d, err := c.Data(); err != nil || c.Seq() != uint64(n+1) || string(d) != fmt.Sprint(n) {
+				t.Fatal(c.Seq(), string(d), err)
+			}
+			n++
+		}
+		if n != count {
+			t.Fatal(n)
+		}
+
+		// Change direction in the middle
+		if !c.Seek(50) || !c.Next() || !c.Prev() || !c.Prev() || c.Seq() != 49 {
+			t.Fatal(c.Seq())
+		}
+		if !c.Next() || !c.Next() || c.Seq() != 51 {
+			t.Fatal(c.Seq())
+		}
+
+		n = 0
+		for ok := c.Last(); ok; ok = c.Prev() {
+			if c.Seq() != uint64(count-n) {
+				t.Fatal(c.Seq(), n)
+			}
+			n++
+		}
+		if n != count {
+			t.Fatal(n)
+		}
+		return c.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,130 @@
+package boltseq
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ErrSnapshotClosed is returned when using a closed or expired snapshot.
+var ErrSnapshotClosed = errors.New("snapshot closed")
+
+// SnapshotOptions configure guardrails of a snapshot.
+type SnapshotOptions struct {
+	// MaxDuration, if positive, is how long the snapshot may be used.
+	// Afterwards Bucket returns ErrSnapshotClosed, though the transaction
+	// is held until Close, as it can't be safely closed under a running
+	// iteration.
+	MaxDuration time.Duration
+
+	// OnExpire, if set, is called with the snapshot age when it's still
+	// open after MaxDuration, e.g. to log a leaked snapshot.
+	OnExpire func(age time.Duration)
+
+	// OnClose, if set, is called with the snapshot age when it's closed,
+	// e.g. to report it to metrics.
+	OnClose func(age time.Duration)
+}
+
+// Snapshot is a stable view of the database held by a long-lived read-only
+// transaction. Open snapshots prevent reuse of pages freed by later writes,
+// so the database file grows while they're held; close them as soon as
+// possible. Writes needing to grow the memory map block until open snapshots
+// are closed, which bolt.Options.InitialMmapSize helps to avoid.
+// Snapshot must not be used from multiple goroutines at once.
+type Snapshot struct {
+	db    *DB
+	tx    *bolt.Tx
+	opts  SnapshotOptions
+	start time.Time
+	timer *time.Timer
+
+	mu      sync.Mutex
+	closed  bool
+	expired bool
+}
+
+// Snapshot starts a snapshot of the database. It must be closed with Close.
+func (db *DB) Snapshot(opts SnapshotOptions) (*Snapshot, error) {
+	tx, err := db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	s := &Snapshot{db: db, tx: tx, opts: opts, start: time.Now()}
+	if opts.MaxDuration > 0 {
+		s.timer = time.AfterFunc(opts.MaxDuration, s.expire)
+	}
+
+	db.mu.Lock()
+	db.snapshots++
+	db.mu.Unlock()
+	return s, nil
+}
+
+// Snapshots returns number of open snapshots.
+func (db *DB) Snapshots() int {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.snapshots
+}
+
+func (s *Snapshot) expire() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.expired = true
+	s.mu.Unlock()
+
+	if s.opts.OnExpire != nil {
+		s.opts.OnExpire(s.Age())
+	}
+}
+
+// Bucket returns read-only boltseq bucket located at path within the snapshot.
+// Returns bolt.ErrBucketNotFound if any bucket along the path doesn't exist.
+func (s *Snapshot) Bucket(path [][]byte) (*ReadOnlyBucket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || s.expired {
+		return nil, ErrSnapshotClosed
+	}
+	b, err := s.db.bucket(s.tx, path)
+	if err != nil {
+		return nil, err
+	}
+	return b.ReadOnly(), nil
+}
+
+// Age returns time since the snapshot was started.
+func (s *Snapshot) Age() time.Duration {
+	return time.Since(s.start)
+}
+
+// Close ends the snapshot, releasing its transaction. Buckets and cursors
+// obtained from it must not be used afterwards. Closing again does nothing.
+func (s *Snapshot) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.db.mu.Lock()
+	s.db.snapshots--
+	s.db.mu.Unlock()
+
+	err := s.tx.Rollback()
+	if s.opts.OnClose != nil {
+		s.opts.OnClose(s.Age())
+	}
+	return err
+}
@@ -0,0 +1,93 @@
+package boltseq
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestDB_Snapshot(t *testing.T) {
+	f, err := ioutil.TempFile("", "boltseq_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.Close()
+
+	// Writes growing the memory map would block on the open snapshot
+	bdb, err := bolt.Open(f.Name(), 0600, &bolt.Options{InitialMmapSize: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bdb.Close()
+
+	db := NewDB(bdb)
+	path := [][]byte{testBucketName}
+	put := func(k string) {
+		err := db.UpdateBucket(path, func(b *Bucket) error {
+			_, err := b.Put([]byte(k), []byte(k))
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	put("a")
+
+	var closedAfter time.Duration
+	s, err := db.Snapshot(SnapshotOptions{OnClose: func(age time.Duration) { closedAfter = age }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.Snapshots() != 1 {
+		t.Fatal(db.Snapshots())
+	}
+	put("b")
+
+	b, err := s.Bucket(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys string
+	err = b.ForEach(func(seq uint64, key, data []byte) error {
+		keys += string(key)
+		return nil
+	})
+	if err != nil || keys != "a" {
+		t.Fatal(keys, err)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if closedAfter <= 0 || db.Snapshots() != 0 {
+		t.Fatal(closedAfter, db.Snapshots())
+	}
+	if _, err := s.Bucket(path); err != ErrSnapshotClosed {
+		t.Fatal(err)
+	}
+
+	expired := make(chan time.Duration, 1)
+	s, err = db.Snapshot(SnapshotOptions{
+		MaxDuration: time.Millisecond,
+		OnExpire:    func(age time.Duration) { expired <- age },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	select {
+	case <-expired:
+	case <-time.After(10 * time.Second):
+		t.Fatal("not expired")
+	}
+	if _, err := s.Bucket(path); err != ErrSnapshotClosed {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,62 @@
+package boltseq
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"time"
+)
+
+// Counters is a Metrics implementation counting operations, cheap enough
+// to be always on. It implements expvar.Var, so it can be published with
+// expvar.Publish. Safe for concurrent use.
+type Counters struct {
+	puts, gets, deletes, steps int64
+	bytesWritten               int64
+}
+
+// DebugStats is a snapshot of Counters.
+type DebugStats struct {
+	Puts         int64 `json:"puts"`
+	Gets         int64 `json:"gets"`
+	Deletes      int64 `json:"deletes"`
+	CursorSteps  int64 `json:"cursor_steps"`
+	BytesWritten int64 `json:"bytes_written"`
+}
+
+// ObservePut counts a put and bytes written.
+func (c *Counters) ObservePut(d time.Duration, bytes int) {
+	atomic.AddInt64(&c.puts, 1)
+	atomic.AddInt64(&c.bytesWritten, int64(bytes))
+}
+
+// ObserveGet counts a get.
+func (c *Counters) ObserveGet(d time.Duration, bytes int) {
+	atomic.AddInt64(&c.gets, 1)
+}
+
+// ObserveDelete counts a deletion.
+func (c *Counters) ObserveDelete(d time.Duration, bytes int) {
+	atomic.AddInt64(&c.deletes, 1)
+}
+
+// ObserveCursorStep counts a cursor step.
+func (c *Counters) ObserveCursorStep(d time.Duration, bytes int) {
+	atomic.AddInt64(&c.steps, 1)
+}
+
+// DebugStats returns current values of the counters.
+func (c *Counters) DebugStats() DebugStats {
+	return DebugStats{
+		Puts:         atomic.LoadInt64(&c.puts),
+		Gets:         atomic.LoadInt64(&c.gets),
+		Deletes:      atomic.LoadInt64(&c.deletes),
+		CursorSteps:  atomic.LoadInt64(&c.steps),
+		BytesWritten: atomic.LoadInt64(&c.bytesWritten),
+	}
+}
+
+// String returns the counters as JSON object.
+func (c *Counters) String() string {
+	p, _ := json.Marshal(c.DebugStats())
+	return string(p)
+}
@@ -0,0 +1,32 @@
+package boltseq
+
+import (
+	"expvar"
+	"testing"
+)
+
+func TestCounters(t *testing.T) {
+	c := &Counters{}
+	var _ expvar.Var = c
+
+	b := NewMemBucket()
+	b.Metrics = c
+	if _, err := b.Put([]byte("a"), []byte("123")); err != nil {
+		t.Fatal(err)
+	}
+	b.Get([]byte("a"))
+	cur := b.Cursor()
+	for ok := cur.First(); ok; ok = cur.Next() {
+	}
+	if err := b.Delete([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := DebugStats{Puts: 1, Gets: 1, Deletes: 1, CursorSteps: 2, BytesWritten: 4}
+	if s := c.DebugStats(); s != exp {
+		t.Fatalf("%+v", s)
+	}
+	if s := c.String(); s != `{"puts":1,"gets":1,"deletes":1,"cursor_steps":2,"bytes_written":4}` {
+		t.Fatal(s)
+	}
+}
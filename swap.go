@@ -0,0 +1,89 @@
+package boltseq
+
+import "bytes"
+
+// Swap exchanges data of two items, along with their expiry times, user flags
+// and origins. Sequence numbers stay with the keys. Stored data is moved without
+// decoding, unless it's encrypted and needs to be bound to the new key. Returns ErrKeyNotFound if either key doesn't exist.
+func (b *Bucket) Swap(keyA, keyB []byte) error {
+	return opError("swap", keyA, 0, b.swap(keyA, keyB, false))
+}
+
+// SwapSeq exchanges sequence numbers of two items, so they swap places in the
+// iteration order. Returns ErrKeyNotFound if either key doesn't exist.
+func (b *Bucket) SwapSeq(keyA, keyB []byte) error {
+	return opError("swap", keyA, 0, b.swap(keyA, keyB, true))
+}
+
+func (b *Bucket) swap(keyA, keyB []byte, seqs bool) error {
+	if b.AppendOnly {
+		return ErrAppendOnly
+	}
+	va, vb := b.get(keyA), b.get(keyB)
+	if va == nil || vb == nil {
+		return ErrKeyNotFound
+	}
+	if !va.IsValid() || !vb.IsValid() {
+		return ErrInvalidValue
+	}
+	if bytes.Equal(keyA, keyB) {
+		return nil
+	}
+
+	seqA, seqB := va.Seq(), vb.Seq()
+	skeyA, skeyB := b.storeKey(keyA), b.storeKey(keyB)
+	if err := b.logSwap(keyA, keyB, va, vb, seqs); err != nil {
+		return err
+	}
+
+	// Copy values, as they're not valid after writes
+	va, vb = va.Clone(), vb.Clone()
+	var na, nb Value
+	var err error
+	if seqs {
+		if na, err = b.rebind(va, skeyA, skeyA, seqB); err != nil {
+			return err
+		}
+		if nb, err = b.rebind(vb, skeyB, skeyB, seqA); err != nil {
+			return err
+		}
+
+		bs := b.bucket(bucketNameSeq)
+		if err := bs.Put(seqKey(seqA), skeyB); err != nil {
+			return err
+		}
+		if err := bs.Put(seqKey(seqB), skeyA); err != nil {
+			return err
+		}
+	} else {
+		if na, err = b.rebind(vb, skeyB, skeyA, seqA); err != nil {
+			return err
+		}
+		if nb, err = b.rebind(va, skeyA, skeyB, seqB); err != nil {
+			return err
+		}
+	}
+
+	bd := b.bucket(bucketNameData)
+	if err := bd.Put(skeyA, na); err != nil {
+		return err
+	}
+	if err := bd.Put(skeyB, nb); err != nil {
+		return err
+	}
+	return b.verify([][]byte{keyA, keyB}, seqA, seqB)
+}
+
+// logSwap records swap of items in the change log.
+func (b *Bucket) logSwap(keyA, keyB []byte, va, vb Value, seqs bool) error {
+	if seqs {
+		if err := b.logUpdate(keyA, keyA, va, vb.Seq()); err != nil {
+			return err
+		}
+		return b.logUpdate(keyB, keyB, vb, va.Seq())
+	}
+	if err := b.logUpdate(keyA, keyB, vb, va.Seq()); err != nil {
+		return err
+	}
+	return b.logUpdate(keyB, keyA, va, vb.Seq())
+}
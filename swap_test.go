@@ -0,0 +1,59 @@
+package boltseq
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestBucket_Swap(t *testing.T) {
+	b := NewMemBucket()
+	b.Dedup = true
+	big := bytes.Repeat([]byte("x"), 100)
+
+	if _, err := b.Put([]byte("cur"), big); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.PutFlags([]byte("prev"), []byte("old"), flagAcked); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Put([]byte("other"), []byte("o")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.Swap([]byte("cur"), []byte("prev")); err != nil {
+		t.Fatal(err)
+	}
+	if v := b.Get([]byte("cur")); v.Seq() != 1 || v.Flags() != flagAcked || string(v.Data()) != "old" {
+		t.Fatal(v)
+	}
+	if v := b.Get([]byte("prev")); v.Seq() != 2 || v.Flags() != 0 || !bytes.Equal(v.Data(), big) {
+		t.Fatal(v)
+	}
+	if s := orderOf(t, b); s != "cur1 prev2 other3 " {
+		t.Fatal(s)
+	}
+
+	if err := b.SwapSeq([]byte("cur"), []byte("other")); err != nil {
+		t.Fatal(err)
+	}
+	if s := orderOf(t, b); s != "other1 prev2 cur3 " {
+		t.Fatal(s)
+	}
+	if v := b.Get([]byte("cur")); string(v.Data()) != "old" {
+		t.Fatal(v)
+	}
+	if k := b.GetSeq(3); string(k) != "cur" {
+		t.Fatal(string(k))
+	}
+
+	if err := b.Swap([]byte("cur"), []byte("x")); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatal(err)
+	}
+	if err := b.Swap([]byte("cur"), []byte("cur")); err != nil {
+		t.Fatal(err)
+	}
+	if refs := blobRefs(b); len(refs) != 1 || refs[0] != 1 {
+		t.Fatal(refs)
+	}
+}
@@ -0,0 +1,156 @@
+package boltseq
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// DefaultSweepBatch is the number of items removed per transaction by sweeps.
+const DefaultSweepBatch = 1000
+
+// DefaultSweepInterval is the interval between sweeps used by StartSweeper
+// if none is configured.
+const DefaultSweepInterval = time.Minute
+
+// SweepConfig configures periodic sweeps of buckets, removing expired items
+// and evicting the oldest ones over quota.
+type SweepConfig struct {
+	// Interval between sweeps of all buckets. Defaults to DefaultSweepInterval.
+	Interval time.Duration
+
+	// Jitter, if set, adds random delay up to Jitter to every interval,
+	// so sweepers of many processes don't run in lockstep.
+	Jitter time.Duration
+
+	// Batch is the maximum number of items removed in a single transaction,
+	// so writers aren't blocked for long. Defaults to DefaultSweepBatch.
+	Batch int
+
+	// OnSweep, if set, is called after every sweep of a bucket with number
+	// of items removed, duration of the sweep and error, if any.
+	OnSweep func(path [][]byte, removed int, d time.Duration, err error)
+}
+
+func (c *SweepConfig) interval() time.Duration {
+	if c.Interval > 0 {
+		return c.Interval
+	}
+	return DefaultSweepInterval
+}
+
+func (c *SweepConfig) batch() int {
+	if c.Batch > 0 {
+		return c.Batch
+	}
+	return DefaultSweepBatch
+}
+
+// Sweep removes expired items of bucket at path and, if the bucket is over
+// its quota with QuotaEvict set, the oldest items. Every batch of items is
+// removed in a separate transaction. Returns number of items removed.
+// Missing buckets are skipped. Waiters for changes, e.g. Tail, are only woken
+// up if any items were removed.
+func (db *DB) Sweep(path [][]byte, batch int) (int, error) {
+	if batch <= 0 {
+		batch = DefaultSweepBatch
+	}
+
+	var total int
+	for {
+		var n int
+		err := db.DB.Update(func(tx *bolt.Tx) error {
+			b, err := db.bucket(tx, path)
+			if err == bolt.ErrBucketNotFound {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if n, err = b.expire(batch); err != nil || n == batch {
+				return err
+			}
+			m, err := b.evictOverQuota(batch - n)
+			n += m
+			return err
+		})
+		total += n
+		if err == nil && n > 0 {
+			db.notify()
+		}
+		if err != nil || n < batch {
+			return total, err
+		}
+	}
+}
+
+// evictOverQuota deletes up to limit oldest items while the bucket exceeds
+// its quota, if eviction is enabled. Returns number of items deleted.
+func (b *Bucket) evictOverQuota(limit int) (int, error) {
+	if b.Quota <= 0 || !b.QuotaEvict {
+		return 0, nil
+	}
+	total, err := b.trackSize()
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for ; n < limit && total > b.Quota; n++ {
+		c := b.rawCursor()
+		if !c.First() {
+			return n, c.Err()
+		}
+		if err := c.Delete(); err != nil {
+			return n, err
+		}
+		if total, err = b.trackSize(); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// Sweeper periodically sweeps buckets of a DB.
+type Sweeper struct {
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// StartSweeper starts sweeping buckets at paths in background according to cfg.
+// Call Stop on the returned sweeper to stop it.
+func (db *DB) StartSweeper(cfg SweepConfig, paths ...[][]byte) *Sweeper {
+	s := &Sweeper{stop: make(chan struct{})}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			d := cfg.interval()
+			if cfg.Jitter > 0 {
+				d += time.Duration(rand.Int63n(int64(cfg.Jitter)))
+			}
+			select {
+			case <-s.stop:
+				return
+			case <-time.After(d):
+			}
+
+			for _, path := range paths {
+				start := time.Now()
+				n, err := db.Sweep(path, cfg.batch())
+				if cfg.OnSweep != nil {
+					cfg.OnSweep(path, n, time.Since(start), err)
+				}
+			}
+		}
+	}()
+	return s
+}
+
+// Stop stops the sweeper, waiting for a sweep in progress to finish.
+func (s *Sweeper) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
@@ -0,0 +1,129 @@
+package boltseq
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDB_sweep(t *testing.T) {
+	bdb, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(bdb.Path())
+
+	now := time.Unix(1000, 0)
+	db := NewDB(bdb)
+	db.Options.Now = func() time.Time { return now }
+	path := [][]byte{testBucketName}
+
+	err = db.UpdateBucket(path, func(b *Bucket) error {
+		for n := 0; n < 5; n++ {
+			if _, err := b.PutTTL([]byte(fmt.Sprint("t", n)), []byte("x"), time.Second); err != nil {
+				return err
+			}
+		}
+		for n := 0; n < 3; n++ {
+			if _, err := b.Put([]byte(fmt.Sprint("k", n)), []byte("x")); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Waiters are only woken up if anything was removed
+	changes := db.changes()
+	if n, err := db.Sweep(path, 2); n != 0 || err != nil {
+		t.Fatal(n, err)
+	}
+	select {
+	case <-changes:
+		t.Fatal("notified")
+	default:
+	}
+	now = now.Add(time.Second)
+	if n, err := db.Sweep(path, 2); n != 5 || err != nil {
+		t.Fatal(n, err)
+	}
+	select {
+	case <-changes:
+	default:
+		t.Fatal("not notified")
+	}
+
+	// Items over quota are evicted, each taking 2+10+1 bytes
+	db.Options.Quota = 26
+	db.Options.QuotaEvict = true
+	if n, err := db.Sweep(path, 2); n != 1 || err != nil {
+		t.Fatal(n, err)
+	}
+	err = db.ViewBucket(path, func(b *Bucket) error {
+		if s := orderOf(t, b); s != "k17 k28 " {
+			t.Fatal(s)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := db.Sweep([][]byte{[]byte("nx")}, 0); n != 0 || err != nil {
+		t.Fatal(n, err)
+	}
+}
+
+func TestDB_sweeper(t *testing.T) {
+	bdb, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(bdb.Path())
+
+	db := NewDB(bdb)
+	path := [][]byte{testBucketName}
+	err = db.UpdateBucket(path, func(b *Bucket) error {
+		_, err := b.PutTTL([]byte("a"), []byte("x"), -time.Second)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	swept := make(chan int, 100)
+	s := db.StartSweeper(SweepConfig{
+		Interval: time.Millisecond,
+		Jitter:   time.Millisecond,
+		OnSweep: func(p [][]byte, removed int, d time.Duration, err error) {
+			if err != nil {
+				t.Error(err)
+			}
+			select {
+			case swept <- removed:
+			default:
+			}
+		},
+	}, path)
+	if n := <-swept; n != 1 {
+		t.Fatal(n)
+	}
+	s.Stop()
+}
+
+func TestSweepConfig_defaults(t *testing.T) {
+	var cfg SweepConfig
+	if d := cfg.interval(); d != DefaultSweepInterval {
+		t.Fatal(d)
+	}
+	cfg.Interval = -time.Second
+	if d := cfg.interval(); d != DefaultSweepInterval {
+		t.Fatal(d)
+	}
+	if n := cfg.batch(); n != DefaultSweepBatch {
+		t.Fatal(n)
+	}
+}
@@ -0,0 +1,126 @@
+package boltseq
+
+import (
+	"context"
+	"math"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// tailBatch is the maximum number of items scanned by Tail per transaction.
+const tailBatch = 1000
+
+// tailPollInterval is how often Tail checks for commits made other than
+// through the DB, e.g. by other processes.
+var tailPollInterval = time.Second
+
+// Tail calls fn for every entry of bucket at path with sequence number
+// greater than fromSeq, in sequence order, then keeps calling it for new
+// entries as they are committed until ctx is done or fn returns an error.
+// Entries are read in short read-only transactions and passed to fn outside
+// of them, so they stay valid and fn may write to the database.
+// Commits through db are noticed immediately, others within a second.
+func Tail(ctx context.Context, db *DB, path [][]byte, fromSeq uint64, fn func(Entry) error) error {
+	return TailFilter(ctx, db, path, fromSeq, nil, fn)
+}
+
+// TailFilter is like Tail, but fn is only called for entries matching f.
+// Entries are filtered while reading, so skipped ones are not copied.
+func TailFilter(ctx context.Context, db *DB, path [][]byte, fromSeq uint64, f *Filter, fn func(Entry) error) error {
+	last := fromSeq
+	for {
+		// Take channel before reading, so no commit is missed
+		changed := db.changes()
+
+		var batch []Entry
+		var scanned uint64
+		var full bool
+		err := db.View(func(tx *bolt.Tx) error {
+			b, err := db.bucket(tx, path)
+			if err == bolt.ErrBucketNotFound {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			batch, scanned, full, err = b.entriesAfter(last, tailBatch, f)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, e := range batch {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+		if scanned > last {
+			last = scanned
+		}
+		if full {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		case <-time.After(tailPollInterval):
+		}
+	}
+}
+
+// WaitForSeq waits until bucket at path holds an item with sequence number
+// of at least seq, or ctx is done.
+func (db *DB) WaitForSeq(ctx context.Context, path [][]byte, seq uint64) error {
+	for {
+		changed := db.changes()
+
+		var max uint64
+		err := db.View(func(tx *bolt.Tx) error {
+			b, err := db.bucket(tx, path)
+			if err == bolt.ErrBucketNotFound {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			max, err = b.MaxSeq()
+			return err
+		})
+		if err != nil || max >= seq {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		case <-time.After(tailPollInterval):
+		}
+	}
+}
+
+// entriesAfter returns copies of entries matching f among up to n items with
+// sequence numbers greater than seq. Returns sequence number of the last
+// scanned item and whether n items were scanned.
+func (b *Bucket) entriesAfter(seq uint64, n int, f *Filter) (entries []Entry, last uint64, full bool, err error) {
+	if seq == math.MaxUint64 {
+		return nil, seq, false, nil
+	}
+	c := b.CursorOpts(CursorOptions{Min: seq + 1, Limit: n})
+	for ok := c.First(); ok; ok = c.Next() {
+		last = c.Seq()
+		if f != nil && !f.match(c) {
+			continue
+		}
+		e, err := c.Entry()
+		if err != nil {
+			return nil, 0, false, opError("get", c.Key(), c.Seq(), err)
+		}
+		entries = append(entries, e.Clone())
+	}
+	return entries, last, c.n >= n, c.Err()
+}
@@ -0,0 +1,125 @@
+package boltseq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestTail(t *testing.T) {
+	bdb, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(bdb.Path())
+
+	db := NewDB(bdb)
+	path := [][]byte{testBucketName}
+	put := func(k string) {
+		err := db.UpdateBucket(path, func(b *Bucket) error {
+			_, err := b.Put([]byte(k), []byte(k))
+			return err
+		})
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	put("a")
+	put("b")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var got string
+	stop := errors.New("stop")
+	err = Tail(ctx, db, path, 1, func(e Entry) error {
+		got += fmt.Sprintf("%s%d ", e.Key, e.Seq)
+		switch e.Seq {
+		case 2:
+			go put("c")
+		case 3:
+			// Writing from fn doesn't deadlock
+			put("d")
+		case 4:
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Fatal(err)
+	}
+	if got != "b2 c3 d4 " {
+		t.Fatal(got)
+	}
+
+	if err := db.WaitForSeq(ctx, path, 4); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := db.WaitForSeq(ctx, path, 5); err != context.DeadlineExceeded {
+		t.Fatal(err)
+	}
+}
+
+func TestTailFilter(t *testing.T) {
+	bdb, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(bdb.Path())
+
+	db := NewDB(bdb)
+	path := [][]byte{testBucketName}
+	put := func(keys ...string) {
+		err := db.UpdateBucket(path, func(b *Bucket) error {
+			for _, k := range keys {
+				if _, err := b.Put([]byte(k), []byte(k)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	put("ui/a", "x", "ui/b", "y")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var got string
+	stop := errors.New("stop")
+	f := &Filter{Prefix: []byte("ui/"), Seq: func(seq uint64) bool { return seq != 3 }}
+	err = TailFilter(ctx, db, path, 0, f, func(e Entry) error {
+		got += fmt.Sprintf("%s%d ", e.Key, e.Seq)
+		switch e.Seq {
+		case 1:
+			go put("z", "ui/c")
+		case 6:
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Fatal(err)
+	}
+	if got != "ui/a1 ui/c6 " {
+		t.Fatal(got)
+	}
+
+	// Nothing follows the maximum sequence number
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = TailFilter(ctx, db, path, math.MaxUint64, nil, func(e Entry) error {
+		return fmt.Errorf("unexpected entry %s%d", e.Key, e.Seq)
+	})
+	if err != context.DeadlineExceeded {
+		t.Fatal(err)
+	}
+}
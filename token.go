@@ -0,0 +1,93 @@
+package boltseq
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+)
+
+var (
+	// ErrInvalidToken is returned for malformed or forged continuation tokens.
+	ErrInvalidToken = errors.New("invalid continuation token")
+
+	// ErrNoTokenKey is returned for continuation tokens if TokenKey isn't set.
+	ErrNoTokenKey = errors.New("continuation token key not set")
+)
+
+// token format version; tokens of version 1 weren't encrypted
+const tokenVersion = 2
+
+// token flags
+const tokenReverse = 1
+
+// ResumeToken returns token for continuing iteration after the current item
+// in the direction the cursor last moved. The token is encrypted and
+// authenticated with TokenKey, so clients can neither read nor fabricate
+// positions. Returns ErrNoTokenKey if TokenKey isn't set.
+func (c *Cursor) ResumeToken() (string, error) {
+	aead, key := c.tokenAEAD()
+	if aead == nil {
+		return "", ErrNoTokenKey
+	}
+	body := make([]byte, 1+binary.MaxVarintLen64)
+	if c.rev {
+		body[0] = tokenReverse
+	}
+	body = body[:1+binary.PutUvarint(body[1:], c.seq)]
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	nonce := mac.Sum(nil)[:nonceSize]
+	p := aead.Seal(append([]byte{tokenVersion}, nonce...), nonce, body, []byte{tokenVersion})
+	return base64.RawURLEncoding.EncodeToString(p), nil
+}
+
+// SeekToken moves cursor to the item following position encoded in token t,
+// in direction of the iteration it was taken from. Subsequent Next or Prev,
+// respectively, continue the iteration. Returns false if there is no item.
+// Like Seek, it starts counting items for CursorOptions.Limit anew.
+// Returns ErrNoTokenKey if TokenKey isn't set.
+func (c *Cursor) SeekToken(t string) (bool, error) {
+	aead, _ := c.tokenAEAD()
+	if aead == nil {
+		return false, ErrNoTokenKey
+	}
+	p, err := base64.RawURLEncoding.DecodeString(t)
+	if err != nil || len(p) <= 1+nonceSize || p[0] != tokenVersion {
+		return false, ErrInvalidToken
+	}
+	body, err := aead.Open(nil, p[1:1+nonceSize], p[1+nonceSize:], p[:1])
+	if err != nil || len(body) < 2 {
+		return false, ErrInvalidToken
+	}
+	seq, n := binary.Uvarint(body[1:])
+	if n <= 0 || 1+n != len(body) {
+		return false, ErrInvalidToken
+	}
+
+	c.n = 0
+	if body[0]&tokenReverse == 0 {
+		return c.count(c.seek(seq + 1)), c.Err()
+	}
+	if seq == 0 {
+		return false, nil
+	}
+	return c.count(c.seekBack(seq - 1)), c.Err()
+}
+
+// tokenAEAD returns cipher encrypting tokens with TokenKey, along with the
+// key, or nil if TokenKey isn't set.
+func (c *Cursor) tokenAEAD() (cipher.AEAD, []byte) {
+	if c.b == nil || c.b.TokenKey == nil {
+		return nil, nil
+	}
+	// Neither fails for 32-byte keys
+	key := sha256.Sum256(c.b.TokenKey)
+	block, _ := aes.NewCipher(key[:])
+	aead, _ := cipher.NewGCM(block)
+	return aead, c.b.TokenKey
+}
@@ -0,0 +1,120 @@
+package boltseq
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"testing"
+)
+
+func TestCursor_token(t *testing.T) {
+	b := NewMemBucket()
+	b.TokenKey = []byte("secret")
+	for n := 0; n < 5; n++ {
+		if _, err := b.Put([]byte(fmt.Sprint(n)), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	token := func(c *Cursor) string {
+		s, err := c.ResumeToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	c := b.Cursor()
+	c.First()
+	c.Next()
+	fwd := token(c)
+	c.Last()
+	c.Prev()
+	rev := token(c)
+
+	c = b.Cursor()
+	if ok, err := c.SeekToken(fwd); !ok || err != nil || c.Seq() != 3 {
+		t.Fatal(ok, err, c.Seq())
+	}
+	if ok, err := c.SeekToken(rev); !ok || err != nil || c.Seq() != 3 {
+		t.Fatal(ok, err, c.Seq())
+	}
+	if !c.Prev() || c.Seq() != 2 {
+		t.Fatal(c.Seq())
+	}
+
+	// Resuming after deleted item
+	if err := b.Delete([]byte("2")); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := c.SeekToken(fwd); !ok || err != nil || c.Seq() != 4 {
+		t.Fatal(ok, err, c.Seq())
+	}
+
+	// Tokens don't expose positions and can't be modified or made up
+	p, err := base64.RawURLEncoding.DecodeString(fwd)
+	if err != nil || bytes.Contains(p[1:], []byte{0, 2}) {
+		t.Fatal(p, err)
+	}
+	p[len(p)/2] ^= 1
+	for _, s := range []string{"AQEE", "AgABAg", base64.RawURLEncoding.EncodeToString(p)} {
+		if _, err := c.SeekToken(s); err != ErrInvalidToken {
+			t.Fatal(s, err)
+		}
+	}
+
+	// Tokens are rejected with another key and require one
+	b.TokenKey = []byte("other")
+	if _, err := c.SeekToken(fwd); err != ErrInvalidToken {
+		t.Fatal(err)
+	}
+	b.TokenKey = nil
+	if _, err := c.ResumeToken(); err != ErrNoTokenKey {
+		t.Fatal(err)
+	}
+	if _, err := c.SeekToken(fwd); err != ErrNoTokenKey {
+		t.Fatal(err)
+	}
+}
+
+func TestCursor_tokenPages(t *testing.T) {
+	b := NewMemBucket()
+	b.TokenKey = []byte("secret")
+	for n := 0; n < 8; n++ {
+		if _, err := b.Put([]byte(fmt.Sprint(n)), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, reverse := range []bool{false, true} {
+		var pages []string
+		var token string
+		for {
+			c := b.CursorOpts(CursorOptions{Limit: 3, Reverse: reverse})
+			var ok bool
+			var err error
+			if token == "" {
+				ok = c.First()
+			} else if ok, err = c.SeekToken(token); err != nil {
+				t.Fatal(err)
+			}
+			if !ok {
+				break
+			}
+			var page string
+			for ; ok; ok = c.Next() {
+				page += string(c.Key())
+			}
+			pages = append(pages, page)
+			if token, err = c.ResumeToken(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		want := "[012 345 67]"
+		if reverse {
+			want = "[765 432 10]"
+		}
+		if s := fmt.Sprint(pages); s != want {
+			t.Fatal(reverse, s)
+		}
+	}
+}
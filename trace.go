@@ -0,0 +1,70 @@
+package boltseq
+
+import "context"
+
+// Tracer starts spans around bucket operations, e.g. using OpenTelemetry.
+type Tracer interface {
+	// Start starts a span for operation op and returns context carrying the span
+	// together with a function finishing it with the operation's error, if any.
+	Start(ctx context.Context, op string) (context.Context, func(err error))
+}
+
+// trace starts a span for op if the bucket has a tracer set.
+func (b *Bucket) trace(ctx context.Context, op string) (context.Context, func(error)) {
+	if b.Tracer == nil {
+		return ctx, func(error) {}
+	}
+	return b.Tracer.Start(ctx, op)
+}
+
+// PutCtx is like Put, but runs within a span started from ctx.
+func (b *Bucket) PutCtx(ctx context.Context, key []byte, value []byte) (uint64, error) {
+	_, finish := b.trace(ctx, "boltseq.Put")
+	seq, err := b.Put(key, value)
+	finish(err)
+	return seq, err
+}
+
+// GetCtx is like Get, but runs within a span started from ctx.
+func (b *Bucket) GetCtx(ctx context.Context, key []byte) Value {
+	_, finish := b.trace(ctx, "boltseq.Get")
+	v := b.Get(key)
+	finish(nil)
+	return v
+}
+
+// DeleteCtx is like Delete, but runs within a span started from ctx.
+func (b *Bucket) DeleteCtx(ctx context.Context, key []byte) error {
+	_, finish := b.trace(ctx, "boltseq.Delete")
+	err := b.Delete(key)
+	finish(err)
+	return err
+}
+
+// DefaultCancelCheckInterval is the default value of Options.CancelCheckInterval.
+const DefaultCancelCheckInterval = 1000
+
+// ForEachCtx is like ForEach, but runs within a span started from ctx.
+// The context carrying the span is passed to fn. Iteration stops with ctx.Err()
+// if the context gets cancelled; this is checked every CancelCheckInterval items.
+func (b *Bucket) ForEachCtx(ctx context.Context, fn func(ctx context.Context, seq uint64, key, data []byte) error) error {
+	ctx, finish := b.trace(ctx, "boltseq.ForEach")
+
+	interval := b.CancelCheckInterval
+	if interval <= 0 {
+		interval = DefaultCancelCheckInterval
+	}
+
+	n := 0
+	err := b.ForEach(func(seq uint64, key, data []byte) error {
+		if n%interval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		n++
+		return fn(ctx, seq, key, data)
+	})
+	finish(err)
+	return err
+}
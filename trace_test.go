@@ -0,0 +1,97 @@
+package boltseq
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+type spanKey struct{}
+
+type recordTracer struct {
+	spans []string
+}
+
+func (t *recordTracer) Start(ctx context.Context, op string) (context.Context, func(error)) {
+	return context.WithValue(ctx, spanKey{}, op), func(error) { t.spans = append(t.spans, op) }
+}
+
+func TestBucket_tracer(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	tr := &recordTracer{}
+	err = db.Update(func(tx *bolt.Tx) error {
+		b := NewBucket(tx.Bucket(testBucketName))
+		b.Tracer = tr
+		ctx := context.Background()
+
+		if _, err := b.PutCtx(ctx, []byte("x"), []byte("v")); err != nil {
+			t.Fatal(err)
+		}
+		if v := b.GetCtx(ctx, []byte("x")); string(v.Data()) != "v" {
+			t.Fatal(v)
+		}
+		return b.ForEachCtx(ctx, func(ctx context.Context, seq uint64, key, data []byte) error {
+			if ctx.Value(spanKey{}) != "boltseq.ForEach" {
+				t.Fatal(ctx.Value(spanKey{}))
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tr.spans) != 3 || tr.spans[0] != "boltseq.Put" || tr.spans[2] != "boltseq.ForEach" {
+		t.Fatal(tr.spans)
+	}
+}
+
+func TestBucket_forEachCtxCancel(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		b := NewBucket(tx.Bucket(testBucketName))
+		for _, k := range []string{"a", "b", "c", "d", "e"} {
+			if _, err := b.Put([]byte(k), nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	n := 0
+	err = db.View(func(tx *bolt.Tx) error {
+		b := NewBucket(tx.Bucket(testBucketName))
+		b.CancelCheckInterval = 2
+		return b.ForEachCtx(ctx, func(ctx context.Context, seq uint64, key, data []byte) error {
+			n++
+			if n == 1 {
+				cancel()
+			}
+			return nil
+		})
+	})
+	if err != context.Canceled {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatal(n)
+	}
+}
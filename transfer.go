@@ -0,0 +1,48 @@
+package boltseq
+
+import "bytes"
+
+// Transfer moves item with the given key from src to dst, keeping its data,
+// expiry time, user flags and origin. The item gets a new sequence number in
+// dst, unless preserveSeq is set, in which case ErrSeqExists is returned
+// before modifying src if dst already uses it. Returns ErrKeyNotFound if key
+// doesn't exist in src. Buckets must belong to the same transaction, which
+// should be rolled back on other errors, as src may have been modified.
+func Transfer(src, dst *Bucket, key []byte, preserveSeq bool) (uint64, error) {
+	v, err := src.GetValue(key)
+	if err != nil {
+		return 0, err
+	}
+	if v == nil {
+		return 0, opError("transfer", key, 0, ErrKeyNotFound)
+	}
+	h, n, ok := parseHeader(v)
+	if !ok {
+		return 0, opError("transfer", key, 0, ErrInvalidValue)
+	}
+
+	// Copy value, as it's not valid after deletion
+	seq, data := v.Seq(), append([]byte{}, v[n:]...)
+	if !preserveSeq {
+		seq = 0
+	} else if dst.seqTaken(seq, key) {
+		return 0, opError("transfer", key, seq, ErrSeqExists)
+	}
+	h.ext, h.nonce, h.origin = false, nil, append([]byte(nil), h.origin...)
+
+	if err := src.Delete(key); err != nil {
+		return 0, err
+	}
+	return dst.putHeader(key, data, seq, h)
+}
+
+// seqTaken tells whether seq is used by an item other than key, including
+// expired ones, so putting key with it would fail.
+func (b *Bucket) seqTaken(seq uint64, key []byte) bool {
+	bs := b.bucket(bucketNameSeq)
+	if bs == nil {
+		return false
+	}
+	k := bs.Get(seqKey(seq))
+	return k != nil && !bytes.Equal(k, b.storeKey(key))
+}
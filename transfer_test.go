@@ -0,0 +1,108 @@
+package boltseq
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTransfer(t *testing.T) {
+	src, dst := NewMemBucket(), NewMemBucket()
+	for _, k := range []string{"a", "b", "c"} {
+		if _, err := src.Put([]byte(k), []byte(k+k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := src.PutFlags([]byte("d"), []byte("dd"), flagPending); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := src.PutTTL([]byte("e"), []byte("ee"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dst.Put([]byte("x"), []byte("xx")); err != nil {
+		t.Fatal(err)
+	}
+
+	if seq, err := Transfer(src, dst, []byte("b"), false); err != nil || seq != 2 {
+		t.Fatal(seq, err)
+	}
+	if seq, err := Transfer(src, dst, []byte("c"), true); err != nil || seq != 3 {
+		t.Fatal(seq, err)
+	}
+	if _, err := Transfer(src, dst, []byte("a"), true); !errors.Is(err, ErrSeqExists) {
+		t.Fatal(err)
+	}
+	if _, err := Transfer(src, dst, []byte("b"), false); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatal(err)
+	}
+	if _, err := Transfer(src, dst, []byte("d"), false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Transfer(src, dst, []byte("e"), false); err != nil {
+		t.Fatal(err)
+	}
+
+	if s := orderOf(t, src); s != "a1 " {
+		t.Fatal(s)
+	}
+	if s := orderOf(t, dst); s != "x1 b2 c3 d4 e5 " {
+		t.Fatal(s)
+	}
+	if v := dst.Get([]byte("c")); string(v.Data()) != "cc" {
+		t.Fatal(v)
+	}
+	if v := dst.Get([]byte("d")); v.Flags() != flagPending || string(v.Data()) != "dd" {
+		t.Fatal(v)
+	}
+	if h, _, _ := parseHeader(dst.get([]byte("e"))); h.flags&flagExpiry == 0 {
+		t.Fatal("expiry lost")
+	}
+
+	// Moving within a bucket gives a new sequence number
+	if seq, err := Transfer(dst, dst, []byte("b"), false); err != nil || seq != 6 {
+		t.Fatal(seq, err)
+	}
+}
+
+func TestTransfer_ttl(t *testing.T) {
+	now := time.Unix(1000, 0)
+	src, dst := NewMemBucket(), NewMemBucket()
+	src.Now = func() time.Time { return now }
+	dst.Now = src.Now
+	if _, err := src.PutTTL([]byte("a"), []byte("a"), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Transfer(src, dst, []byte("a"), false); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fresh bucket learns it holds expiring items
+	now = now.Add(time.Hour)
+	if dst.Get([]byte("a")) != nil || dst.Cursor().First() {
+		t.Fatal("expired item visible")
+	}
+	if n, err := dst.ExpireNow(); err != nil || n != 1 {
+		t.Fatal(n, err)
+	}
+}
+
+func TestTransfer_seqTakenByExpired(t *testing.T) {
+	now := time.Unix(1000, 0)
+	src, dst := NewMemBucket(), NewMemBucket()
+	dst.Now = func() time.Time { return now }
+	if _, err := src.Put([]byte("a"), []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dst.PutTTL([]byte("x"), []byte("x"), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Hour)
+
+	// Expired item still holds the sequence number, src is left untouched
+	if _, err := Transfer(src, dst, []byte("a"), true); !errors.Is(err, ErrSeqExists) {
+		t.Fatal(err)
+	}
+	if v := src.Get([]byte("a")); string(v.Data()) != "a" {
+		t.Fatal(v)
+	}
+}
@@ -0,0 +1,81 @@
+package boltseq
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrExpired is returned when loading data of an expired item.
+var ErrExpired = errors.New("item expired")
+
+// meta key marking buckets holding items with expiry
+var metaKeyTTL = []byte("ttl")
+
+// PutTTL is like Put, but the item expires after ttl. Expired items are
+// treated as absent by reads and iteration, and removed by ExpireNow.
+func (b *Bucket) PutTTL(key, value []byte, ttl time.Duration) (uint64, error) {
+	h := header{flags: flagExpiry, expires: b.now().Add(ttl).UnixNano()}
+	return b.putHeader(key, value, 0, h)
+}
+
+// hasTTL tells whether the bucket may hold items with expiry.
+func (b *Bucket) hasTTL() bool {
+	bm := b.bucket(bucketNameMeta)
+	return bm != nil && bm.Get(metaKeyTTL) != nil
+}
+
+// markTTL marks the bucket as holding items with expiry, so reads and
+// iteration check it.
+func (b *Bucket) markTTL() error {
+	if b.hasTTL() {
+		return nil
+	}
+	bm, err := b.createBucket(bucketNameMeta)
+	if err != nil {
+		return err
+	}
+	return bm.Put(metaKeyTTL, []byte{1})
+}
+
+func (b *Bucket) now() time.Time {
+	if b.Now != nil {
+		return b.Now()
+	}
+	return time.Now()
+}
+
+// expired tells whether stored value v is expired.
+func (b *Bucket) expired(v Value) bool {
+	h, _, ok := parseHeader(v)
+	return ok && h.expired(b.now().UnixNano())
+}
+
+// ExpireNow deletes expired items. Returns number of items deleted.
+func (b *Bucket) ExpireNow() (int, error) {
+	return b.expire(0)
+}
+
+// expire deletes up to limit expired items, or all if limit is not positive.
+func (b *Bucket) expire(limit int) (int, error) {
+	bd := b.bucket(bucketNameData)
+	if bd == nil || !b.hasTTL() {
+		return 0, nil
+	}
+
+	// Collect keys first, as the bucket can't be modified while iterating
+	var keys [][]byte
+	now := b.now().UnixNano()
+	c := bd.Cursor()
+	for k, v := c.First(); k != nil && (limit <= 0 || len(keys) < limit); k, v = c.Next() {
+		if h, _, ok := parseHeader(v); ok && h.expired(now) {
+			keys = append(keys, append([]byte{}, b.userKey(k)...))
+		}
+	}
+
+	for n, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return n, err
+		}
+	}
+	return len(keys), nil
+}
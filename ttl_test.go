@@ -0,0 +1,172 @@
+package boltseq
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestBucket_ttl(t *testing.T) {
+	now := time.Unix(1000, 0)
+	b := NewMemBucket()
+	b.Now = func() time.Time { return now }
+
+	if _, err := b.Put([]byte("a"), []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.PutTTL([]byte("b"), []byte("2"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.PutTTL([]byte("c"), []byte("3"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if v := b.Get([]byte("b")); v.Seq() != 2 || string(v.Data()) != "2" {
+		t.Fatal(v)
+	}
+
+	now = now.Add(time.Minute)
+	if v := b.Get([]byte("b")); v != nil {
+		t.Fatal(v)
+	}
+	if k := b.GetSeq(2); k != nil {
+		t.Fatal(k)
+	}
+	if s := orderOf(t, b); s != "a1 c3 " {
+		t.Fatal(s)
+	}
+	c := b.Cursor()
+	if c.SeekKey([]byte("b")) || c.Err() != nil {
+		t.Fatal(c.Err())
+	}
+
+	// Expired items are still stored until removed
+	if b.get([]byte("b")) == nil {
+		t.Fatal("expired item removed")
+	}
+	if n, err := b.ExpireNow(); n != 1 || err != nil {
+		t.Fatal(n, err)
+	}
+	if b.get([]byte("b")) != nil {
+		t.Fatal("expired item not removed")
+	}
+
+	// Put without TTL removes expiry
+	if _, err := b.Put([]byte("c"), []byte("3")); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Hour)
+	if n, err := b.ExpireNow(); n != 0 || err != nil {
+		t.Fatal(n, err)
+	}
+	if s := orderOf(t, b); s != "a1 c4 " {
+		t.Fatal(s)
+	}
+}
+
+// expiredFixture returns bucket holding a=1, x=2 expired, b=3 and c=4.
+func expiredFixture(t *testing.T) *Bucket {
+	now := time.Unix(1000, 0)
+	b := NewMemBucket()
+	b.Now = func() time.Time { return now }
+	b.Paranoid = true
+	for _, k := range []string{"a", "x", "b", "c"} {
+		var err error
+		if k == "x" {
+			_, err = b.PutTTL([]byte(k), []byte(k), time.Minute)
+		} else {
+			_, err = b.Put([]byte(k), []byte(k))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	now = now.Add(time.Hour)
+	return b
+}
+
+func TestBucket_ttlMaintenance(t *testing.T) {
+	// Compact renumbers expired items too
+	b := expiredFixture(t)
+	if err := b.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	if v := b.get([]byte("x")); v.Seq() != 2 || !bytes.Equal(b.bucket(bucketNameSeq).Get(seqKey(2)), []byte("x")) {
+		t.Fatal(v.Seq())
+	}
+	if n, err := b.ExpireNow(); n != 1 || err != nil {
+		t.Fatal(n, err)
+	}
+	if s := orderOf(t, b); s != "a1 b3 c4 " {
+		t.Fatal(s)
+	}
+
+	// Moving doesn't take sequence numbers of expired items
+	b = expiredFixture(t)
+	if err := b.MoveBefore([]byte("c"), []byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := b.ExpireNow(); n != 1 || err != nil {
+		t.Fatal(n, err)
+	}
+	if s := orderOf(t, b); s != "a1 c3 b4 " {
+		t.Fatal(s)
+	}
+	if gaps, err := b.Gaps(); err != nil || len(gaps) != 1 || gaps[0] != (SeqRange{2, 2}) {
+		t.Fatal(gaps, err)
+	}
+
+	// Eviction drops expired items before live ones
+	b = expiredFixture(t)
+	size, err := b.trackSize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Quota, b.QuotaEvict = size, true
+	if _, err := b.Put([]byte("d"), []byte("d")); err != nil {
+		t.Fatal(err)
+	}
+	if s := orderOf(t, b); s != "a1 b3 c4 d5 " {
+		t.Fatal(s)
+	}
+	if b.get([]byte("x")) != nil {
+		t.Fatal("expired item kept")
+	}
+}
+
+func TestBucket_ttlAbsent(t *testing.T) {
+	for _, put := range []func(b *Bucket) (uint64, error){
+		func(b *Bucket) (uint64, error) { return b.PutIfAbsent([]byte("x"), []byte("new")) },
+		func(b *Bucket) (uint64, error) { b.AppendOnly = true; return b.Put([]byte("x"), []byte("new")) },
+		func(b *Bucket) (uint64, error) {
+			b.Overwrite = OverwriteReject
+			return b.Put([]byte("x"), []byte("new"))
+		},
+		func(b *Bucket) (uint64, error) {
+			b.AppendOnly = true
+			_, seq, _, err := b.GetOrPut([]byte("x"), []byte("new"))
+			return seq, err
+		},
+	} {
+		b := expiredFixture(t)
+		if seq, err := put(b); err != nil || seq != 5 {
+			t.Fatal(seq, err)
+		}
+		if v := b.Get([]byte("x")); string(v.Data()) != "new" {
+			t.Fatal(v)
+		}
+		if s := orderOf(t, b); s != "a1 b3 c4 x5 " {
+			t.Fatal(s)
+		}
+	}
+}
+
+func TestBucket_ttlReader(t *testing.T) {
+	b := NewMemBucket()
+	b.ChunkSize = 100
+	if _, err := b.PutTTL([]byte("a"), make([]byte, 1000), -time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.GetReader([]byte("a")); err != ErrKeyNotFound {
+		t.Fatal(err)
+	}
+}
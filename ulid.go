@@ -0,0 +1,282 @@
+package boltseq
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// ID is a 128-bit time-sortable identifier laid out like ULID: 48-bit Unix
+// time in milliseconds followed by 80 random bits, both big-endian.
+type ID [16]byte
+
+// ErrInvalidID is returned when an ID can't be parsed.
+var ErrInvalidID = errors.New("invalid id")
+
+// idEncoding is Crockford's base32 used by ULID.
+var idEncoding = base32.NewEncoding("0123456789ABCDEFGHJKMNPQRSTVWXYZ").WithPadding(base32.NoPadding)
+
+// Time returns time encoded in the ID.
+func (id ID) Time() time.Time {
+	var ms [8]byte
+	copy(ms[2:], id[:6])
+	return time.Unix(0, int64(binary.BigEndian.Uint64(ms[:]))*int64(time.Millisecond))
+}
+
+// String returns 26-character base32 representation of the ID. Note it is
+// not the canonical ULID text encoding, but sorts the same way.
+func (id ID) String() string {
+	return idEncoding.EncodeToString(id[:])
+}
+
+// ParseID parses ID returned by ID.String.
+func ParseID(s string) (ID, error) {
+	var id ID
+	b, err := idEncoding.DecodeString(s)
+	if err != nil || len(b) != len(id) {
+		return id, ErrInvalidID
+	}
+	copy(id[:], b)
+	return id, nil
+}
+
+// newID returns ID for time t, greater than last.
+func newID(t time.Time, last ID) (ID, error) {
+	var id ID
+	ms := uint64(t.UnixNano() / int64(time.Millisecond))
+	var tb [8]byte
+	binary.BigEndian.PutUint64(tb[:], ms)
+	copy(id[:6], tb[2:])
+
+	if _, err := rand.Read(id[6:]); err != nil {
+		return id, err
+	}
+
+	// Within the same millisecond keep IDs monotonic by incrementing the last one
+	if bytes.Compare(id[:], last[:]) <= 0 {
+		id = last
+		for n := len(id) - 1; n >= 0; n-- {
+			if id[n]++; id[n] != 0 {
+				break
+			}
+		}
+	}
+	return id, nil
+}
+
+// sub-bucket holding id->key mapping
+var bucketNameID = []byte("id")
+
+// IDValue is a value stored in IDBucket, consisting of ID and data.
+type IDValue []byte
+
+// IsValid tells whether value is valid.
+func (v IDValue) IsValid() bool {
+	return len(v) >= len(ID{})
+}
+
+// ID returns ID of the value, or zero ID if value is invalid.
+func (v IDValue) ID() (id ID) {
+	if v.IsValid() {
+		copy(id[:], v)
+	}
+	return
+}
+
+// Data returns data part of the value, or nil if value is invalid.
+func (v IDValue) Data() []byte {
+	if !v.IsValid() {
+		return nil
+	}
+	return v[len(ID{}):]
+}
+
+// IDBucket is like Bucket, but items are ordered by 128-bit time-sortable IDs
+// instead of a 64-bit counter. IDs are globally unique with high probability,
+// so items of buckets from different machines can be merged in rough time
+// order. IDBucket and Bucket must not share a location.
+type IDBucket struct {
+	loc Store
+
+	// Now returns current time, used to generate IDs. Defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewIDBucket creates an ID-ordered bucket at given location.
+func NewIDBucket(loc Location) *IDBucket {
+	return &IDBucket{loc: BoltStore(loc)}
+}
+
+// Put adds key-value pair into the bucket, giving it a new ID.
+func (b *IDBucket) Put(key []byte, value []byte) (ID, error) {
+	bd, err := b.loc.CreateBucketIfNotExists(bucketNameData)
+	if err != nil {
+		return ID{}, err
+	}
+	bi, err := b.loc.CreateBucketIfNotExists(bucketNameID)
+	if err != nil {
+		return ID{}, err
+	}
+
+	if v := IDValue(bd.Get(key)); v != nil {
+		if !v.IsValid() {
+			return ID{}, ErrInvalidValue
+		}
+		if err := bi.Delete(v[:len(ID{})]); err != nil {
+			return ID{}, err
+		}
+	}
+
+	var last ID
+	if k, _ := bi.Cursor().Last(); k != nil {
+		copy(last[:], k)
+	}
+	now := time.Now
+	if b.Now != nil {
+		now = b.Now
+	}
+	id, err := newID(now(), last)
+	if err != nil {
+		return id, err
+	}
+
+	val := make(IDValue, len(id)+len(value))
+	copy(val, id[:])
+	copy(val[len(id):], value)
+
+	setFillPercent(bi, 1)
+	if err := bi.Put(val[:len(id)], key); err != nil {
+		return id, err
+	}
+	return id, bd.Put(key, val)
+}
+
+// Get returns value for the key.
+func (b *IDBucket) Get(key []byte) IDValue {
+	bd := b.loc.Bucket(bucketNameData)
+	if bd == nil {
+		return nil
+	}
+	return IDValue(bd.Get(key))
+}
+
+// GetID returns key with the given ID.
+func (b *IDBucket) GetID(id ID) []byte {
+	bi := b.loc.Bucket(bucketNameID)
+	if bi == nil {
+		return nil
+	}
+	return bi.Get(id[:])
+}
+
+// Delete deletes a key.
+func (b *IDBucket) Delete(key []byte) error {
+	bd, bi := b.loc.Bucket(bucketNameData), b.loc.Bucket(bucketNameID)
+	if bd == nil || bi == nil {
+		return nil
+	}
+	v := IDValue(bd.Get(key))
+	if v == nil {
+		return nil
+	}
+	if !v.IsValid() {
+		return ErrInvalidValue
+	}
+	if err := bi.Delete(v[:len(ID{})]); err != nil {
+		return err
+	}
+	return bd.Delete(key)
+}
+
+// Cursor returns iterator over the bucket in ID order.
+func (b *IDBucket) Cursor() *IDCursor {
+	c := &IDCursor{}
+	if bi := b.loc.Bucket(bucketNameID); bi != nil {
+		c.ci = bi.Cursor()
+	}
+	if bd := b.loc.Bucket(bucketNameData); bd != nil {
+		c.dp.c = bd.Cursor()
+	}
+	return c
+}
+
+// IDCursor iterates over IDBucket in ID order.
+type IDCursor struct {
+	ci  KVCursor
+	dp  pointer
+	id  ID
+	key []byte
+	err error
+}
+
+func (c *IDCursor) sync(id, key []byte) bool {
+	if id == nil {
+		return false
+	}
+	if len(id) != len(c.id) {
+		c.err = ErrInvalidKey
+		return false
+	}
+	copy(c.id[:], id)
+	c.key = key
+	return true
+}
+
+// First moves cursor to the first item. Returns false on empty bucket.
+func (c *IDCursor) First() bool {
+	return c.ci != nil && c.sync(c.ci.First())
+}
+
+// Last moves cursor to the last item. Returns false on empty bucket.
+func (c *IDCursor) Last() bool {
+	return c.ci != nil && c.sync(c.ci.Last())
+}
+
+// Next moves cursor to the next item. Returns false at the end of the bucket.
+func (c *IDCursor) Next() bool {
+	return c.ci != nil && c.sync(c.ci.Next())
+}
+
+// Prev moves cursor to the previous item. Returns false at the beginning of the bucket.
+func (c *IDCursor) Prev() bool {
+	return c.ci != nil && c.sync(c.ci.Prev())
+}
+
+// Seek moves cursor to the item with the given ID, or the next one.
+// Returns false if no item.
+func (c *IDCursor) Seek(id ID) bool {
+	return c.ci != nil && c.sync(c.ci.Seek(id[:]))
+}
+
+// Err returns error, if any.
+func (c *IDCursor) Err() error {
+	return c.err
+}
+
+// ID returns current ID.
+func (c *IDCursor) ID() ID {
+	return c.id
+}
+
+// Key returns current key.
+func (c *IDCursor) Key() []byte {
+	return c.key
+}
+
+// Data returns current data for the key.
+func (c *IDCursor) Data() ([]byte, error) {
+	if c.key == nil {
+		return nil, ErrInvalidKey
+	}
+	v, ok := c.dp.Get(c.key)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+	if !IDValue(v).IsValid() {
+		return nil, ErrInvalidValue
+	}
+	return IDValue(v).Data(), nil
+}
@@ -0,0 +1,78 @@
+package boltseq
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestIDBucket(t *testing.T) {
+	db, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(db.Path())
+
+	now := time.Unix(1600000000, 0)
+	err = db.Update(func(tx *bolt.Tx) error {
+		b := NewIDBucket(tx.Bucket(testBucketName))
+		b.Now = func() time.Time { return now }
+
+		// IDs within the same millisecond are monotonic
+		var ids []ID
+		for _, k := range []string{"a", "b", "c", "b"} {
+			id, err := b.Put([]byte(k), []byte(k))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !id.Time().Equal(now) {
+				t.Fatal(id.Time())
+			}
+			if len(ids) > 0 && id.String() <= ids[len(ids)-1].String() {
+				t.Fatal(ids, id)
+			}
+			ids = append(ids, id)
+		}
+
+		if k := b.GetID(ids[3]); string(k) != "b" {
+			t.Fatal(k)
+		}
+		if k := b.GetID(ids[1]); k != nil {
+			t.Fatal(k)
+		}
+		if v := b.Get([]byte("a")); v.ID() != ids[0] || string(v.Data()) != "a" {
+			t.Fatal(v)
+		}
+
+		var keys string
+		c := b.Cursor()
+		for ok := c.Seek(ids[1]); ok; ok = c.Next() {
+			d, err := c.Data()
+			if err != nil {
+				t.Fatal(err)
+			}
+			keys += string(d)
+		}
+		if keys != "cb" {
+			t.Fatal(keys)
+		}
+
+		if err := b.Delete([]byte("c")); err != nil {
+			t.Fatal(err)
+		}
+		if !c.First() || !c.Next() || c.ID() != ids[3] || c.Next() {
+			t.Fatal(c.ID())
+		}
+
+		id, err := ParseID(ids[2].String())
+		if err != nil || id != ids[2] {
+			t.Fatal(id, err)
+		}
+		return c.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
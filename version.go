@@ -0,0 +1,177 @@
+package boltseq
+
+import (
+	"bytes"
+	"errors"
+)
+
+// Version is a format version of a bucket.
+type Version byte
+
+const (
+	// Version0 is the original format, where values may have legacy 8-byte
+	// headers holding sequence number only. It's assumed for unstamped buckets.
+	Version0 Version = iota
+	// Version1 stores every value with versioned header.
+	Version1
+
+	// CurrentVersion is the latest format version supported.
+	CurrentVersion = Version1
+)
+
+// meta keys holding format version and migration progress
+var (
+	metaKeyVersion = []byte("version")
+	metaKeyMigrate = []byte("migrate")
+)
+
+// migrateBatch is the number of items migrated per batch.
+const migrateBatch = 1000
+
+// ErrUnsupportedVersion is returned for buckets of unknown format versions.
+var ErrUnsupportedVersion = errors.New("unsupported format version")
+
+// Version returns format version of the bucket. It's read once per Bucket,
+// so it's cheap to call on every write.
+func (b *Bucket) Version() Version {
+	if !b.verOK {
+		b.ver, b.verOK = b.storedVersion(), true
+	}
+	return b.ver
+}
+
+// storedVersion returns format version stamped in the meta sub-bucket.
+func (b *Bucket) storedVersion() Version {
+	if bm := b.bucket(bucketNameMeta); bm != nil {
+		if v := bm.Get(metaKeyVersion); len(v) == 1 {
+			return Version(v[0])
+		}
+	}
+	return Version0
+}
+
+func (b *Bucket) setVersion(ver Version) error {
+	bm, err := b.createBucket(bucketNameMeta)
+	if err != nil {
+		return err
+	}
+	if err := bm.Put(metaKeyVersion, []byte{byte(ver)}); err != nil {
+		return err
+	}
+	b.ver, b.verOK = ver, true
+	return nil
+}
+
+// createDataBuckets returns data and seq sub-buckets, creating them if needed.
+// New buckets are stamped with CurrentVersion, unless stamped already.
+func (b *Bucket) createDataBuckets() (bd, bs KVBucket, err error) {
+	if b.bucket(bucketNameData) == nil {
+		if bm := b.bucket(bucketNameMeta); bm == nil || bm.Get(metaKeyVersion) == nil {
+			if err := b.setVersion(CurrentVersion); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	if bd, err = b.createBucket(bucketNameData); err != nil {
+		return nil, nil, err
+	}
+	if bs, err = b.createBucket(bucketNameSeq); err != nil {
+		return nil, nil, err
+	}
+	return bd, bs, nil
+}
+
+// Migrate converts all items of the bucket to format version to and stamps
+// the bucket with it. Migration is done in batches recording progress, so
+// an interrupted migration continues where it stopped.
+// Use DB.Migrate to commit every batch in its own transaction.
+func Migrate(b *Bucket, to Version) error {
+	for {
+		done, err := b.migrateBatch(to, migrateBatch)
+		if done || err != nil {
+			return err
+		}
+	}
+}
+
+// Migrate migrates bucket at path to format version to, committing every
+// batch of items in a separate transaction.
+func (db *DB) Migrate(path [][]byte, to Version) error {
+	for {
+		var done bool
+		err := db.UpdateBucket(path, func(b *Bucket) (err error) {
+			done, err = b.migrateBatch(to, migrateBatch)
+			return err
+		})
+		if done || err != nil {
+			return err
+		}
+	}
+}
+
+// migrateBatch migrates up to n items to version to. Returns true once all
+// items have been migrated.
+func (b *Bucket) migrateBatch(to Version, n int) (bool, error) {
+	if to > CurrentVersion {
+		return false, ErrUnsupportedVersion
+	}
+	bm, err := b.createBucket(bucketNameMeta)
+	if err != nil {
+		return false, err
+	}
+
+	// Progress is target version followed by the last migrated key
+	var last []byte
+	if p := bm.Get(metaKeyMigrate); len(p) > 0 && Version(p[0]) == to {
+		last = append([]byte{}, p[1:]...)
+	} else if b.Version() == to {
+		return true, nil
+	}
+
+	var keys [][]byte
+	if bd := b.bucket(bucketNameData); bd != nil {
+		c := bd.Cursor()
+		k, _ := c.First()
+		if last != nil {
+			if k, _ = c.Seek(last); bytes.Equal(k, last) {
+				k, _ = c.Next()
+			}
+		}
+		for ; k != nil && len(keys) < n; k, _ = c.Next() {
+			keys = append(keys, append([]byte{}, k...))
+		}
+	}
+
+	for _, k := range keys {
+		if err := b.migrateValue(k, to); err != nil {
+			return false, err
+		}
+	}
+
+	if len(keys) < n {
+		if err := bm.Delete(metaKeyMigrate); err != nil {
+			return false, err
+		}
+		return true, b.setVersion(to)
+	}
+	return false, bm.Put(metaKeyMigrate, append([]byte{byte(to)}, keys[len(keys)-1]...))
+}
+
+// migrateValue rewrites value of the key in format version to.
+func (b *Bucket) migrateValue(key []byte, to Version) error {
+	v := b.get(key)
+	h, n, ok := parseHeader(v)
+	if !ok {
+		return ErrInvalidValue
+	}
+	if h.flags != 0 || (v[0]&0x80 != 0) == (to >= Version1) {
+		return nil
+	}
+
+	h.ext = to >= Version1
+	nv := b.arena.newValue(v.Seq(), &h, v[n:])
+	if err := b.bucket(bucketNameData).Put(b.storeKey(key), nv); err != nil {
+		return err
+	}
+	return b.growSize(int64(len(nv) - len(v)))
+}
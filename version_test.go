@@ -0,0 +1,157 @@
+package boltseq
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestMigrate(t *testing.T) {
+	b := NewMemBucket()
+	b.Quota = 1000
+	if err := b.setVersion(Version0); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"a", "b", "c", "d", "e"} {
+		if _, err := b.Put([]byte(k), []byte(k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if v := b.Version(); v != Version0 {
+		t.Fatal(v)
+	}
+
+	// Interrupted migration continues where it stopped
+	if done, err := b.migrateBatch(Version1, 2); done || err != nil {
+		t.Fatal(done, err)
+	}
+	if v := b.get([]byte("b")); v[0]&0x80 == 0 {
+		t.Fatal(v)
+	}
+	if v := b.get([]byte("c")); v[0]&0x80 != 0 {
+		t.Fatal(v)
+	}
+	if v := b.Version(); v != Version0 {
+		t.Fatal(v)
+	}
+	if err := Migrate(b, Version1); err != nil {
+		t.Fatal(err)
+	}
+	if v := b.Version(); v != Version1 {
+		t.Fatal(v)
+	}
+
+	for _, k := range []string{"a", "e"} {
+		if v := b.get([]byte(k)); len(v) != 11 || v[0]&0x80 == 0 {
+			t.Fatal(v)
+		}
+	}
+	if size, _ := b.storedSize(); size != 5*12 {
+		t.Fatal(size)
+	}
+	if s := orderOf(t, b); s != "a1 b2 c3 d4 e5 " {
+		t.Fatal(s)
+	}
+
+	// New values use versioned header
+	if _, err := b.Put([]byte("f"), []byte("f")); err != nil {
+		t.Fatal(err)
+	}
+	if v := b.get([]byte("f")); len(v) != 11 || v.Seq() != 6 || string(v.Data()) != "f" {
+		t.Fatal(v)
+	}
+
+	// Downgrade
+	if err := Migrate(b, Version0); err != nil {
+		t.Fatal(err)
+	}
+	if v := b.get([]byte("f")); len(v) != 9 || v.Seq() != 6 {
+		t.Fatal(v)
+	}
+
+	if err := Migrate(b, CurrentVersion+1); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatal(err)
+	}
+	if err := b.setVersion(CurrentVersion + 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Put([]byte("g"), nil); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Fatal(err)
+	}
+}
+
+func TestDB_migrate(t *testing.T) {
+	bdb, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(bdb.Path())
+
+	db := NewDB(bdb)
+	path := [][]byte{testBucketName}
+	err = db.UpdateBucket(path, func(b *Bucket) error {
+		_, err := b.Put([]byte("a"), []byte("1"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Migrate(path, Version1); err != nil {
+		t.Fatal(err)
+	}
+	err = db.View(func(tx *bolt.Tx) error {
+		b := NewBucket(tx.Bucket(testBucketName))
+		if v := b.Version(); v != Version1 {
+			t.Fatal(v)
+		}
+		if v := b.Get([]byte("a")); v.Seq() != 1 || string(v.Data()) != "1" {
+			t.Fatal(v)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBucket_versionStamp(t *testing.T) {
+	b := NewMemBucket()
+	if _, err := b.Put([]byte("a"), []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if v := b.storedVersion(); v != CurrentVersion {
+		t.Fatal(v)
+	}
+	if v := b.get([]byte("a")); v[0]&0x80 == 0 {
+		t.Fatal(v)
+	}
+
+	// Existing unstamped buckets keep their format
+	if err := b.bucket(bucketNameMeta).Delete(metaKeyVersion); err != nil {
+		t.Fatal(err)
+	}
+	b = NewStoreBucket(b.loc)
+	if _, err := b.Put([]byte("b"), []byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	if v := b.storedVersion(); v != Version0 {
+		t.Fatal(v)
+	}
+	if v := b.get([]byte("b")); v[0]&0x80 != 0 {
+		t.Fatal(v)
+	}
+
+	// Version is read once per bucket
+	if err := b.bucket(bucketNameMeta).Put(metaKeyVersion, []byte{byte(Version1)}); err != nil {
+		t.Fatal(err)
+	}
+	if v := b.Version(); v != Version0 {
+		t.Fatal(v)
+	}
+	if v := NewStoreBucket(b.loc).Version(); v != Version1 {
+		t.Fatal(v)
+	}
+}
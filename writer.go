@@ -0,0 +1,129 @@
+package boltseq
+
+import (
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// DefaultWriterMaxOps is the default value of Writer.MaxOps.
+const DefaultWriterMaxOps = 1000
+
+// BatchError is returned by Writer when a batch of operations fails.
+// None of the operations of the batch are stored.
+type BatchError struct {
+	Ops int // number of discarded operations
+	Err error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch of %d operations failed: %v", e.Ops, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// Writer groups a stream of Put and Delete operations into transactions,
+// committing every MaxOps operations or MaxBytes bytes written, whichever
+// comes first. If an operation fails, the whole batch is discarded and
+// *BatchError returned; the next operation starts a new batch.
+// Writer is not safe for concurrent use.
+type Writer struct {
+	// MaxOps is the number of operations per transaction.
+	// Defaults to DefaultWriterMaxOps.
+	MaxOps int
+
+	// MaxBytes, if positive, limits total size of keys and values per transaction.
+	MaxBytes int
+
+	// OnBatch, if set, is called after every batch with the number of its
+	// operations and error, if any.
+	OnBatch func(ops int, err error)
+
+	db   *DB
+	path [][]byte
+
+	tx    *bolt.Tx
+	b     *Bucket
+	ops   int
+	bytes int
+}
+
+// NewWriter returns writer for the bucket at path of db.
+func NewWriter(db *DB, path [][]byte) *Writer {
+	return &Writer{db: db, path: path}
+}
+
+// Put adds key-value pair to the current batch.
+func (w *Writer) Put(key, value []byte) error {
+	return w.do(len(key)+len(value), func(b *Bucket) error {
+		_, err := b.Put(key, value)
+		return err
+	})
+}
+
+// Delete adds deletion of the key to the current batch.
+func (w *Writer) Delete(key []byte) error {
+	return w.do(len(key), func(b *Bucket) error {
+		return b.Delete(key)
+	})
+}
+
+func (w *Writer) do(size int, op func(b *Bucket) error) error {
+	if w.tx == nil {
+		tx, b, err := w.db.beginBucket(w.path)
+		if err != nil {
+			return err
+		}
+		w.tx, w.b = tx, b
+	}
+
+	w.ops++
+	w.bytes += size
+	if err := op(w.b); err != nil {
+		return w.end(err)
+	}
+
+	max := w.MaxOps
+	if max <= 0 {
+		max = DefaultWriterMaxOps
+	}
+	if w.ops >= max || w.MaxBytes > 0 && w.bytes >= w.MaxBytes {
+		return w.Flush()
+	}
+	return nil
+}
+
+// Flush commits the current batch.
+func (w *Writer) Flush() error {
+	if w.tx == nil {
+		return nil
+	}
+	return w.end(nil)
+}
+
+// Close flushes pending operations.
+func (w *Writer) Close() error {
+	return w.Flush()
+}
+
+// end finishes the current batch, rolling it back if err is not nil.
+func (w *Writer) end(err error) error {
+	if err != nil {
+		w.tx.Rollback()
+	} else if err = w.tx.Commit(); err == nil {
+		w.db.notify()
+	}
+
+	ops := w.ops
+	w.tx, w.b, w.ops, w.bytes = nil, nil, 0, 0
+
+	if w.OnBatch != nil {
+		w.OnBatch(ops, err)
+	}
+	if err != nil {
+		return &BatchError{Ops: ops, Err: err}
+	}
+	return nil
+}
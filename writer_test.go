@@ -0,0 +1,63 @@
+package boltseq
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	bdb, err := newTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(bdb.Path())
+
+	db := NewDB(bdb)
+	path := [][]byte{testBucketName}
+	db.Options.Limits.MaxValueSize = 10
+
+	var batches []int
+	w := NewWriter(db, path)
+	w.MaxOps = 4
+	w.OnBatch = func(ops int, err error) {
+		batches = append(batches, ops)
+	}
+
+	for n := 0; n < 10; n++ {
+		if err := w.Put([]byte(fmt.Sprint(n)), []byte("v")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Delete([]byte("9")); err != nil {
+		t.Fatal(err)
+	}
+
+	// Failure discards the batch holding "8" and "9" (already deleted)
+	err = w.Put([]byte("x"), make([]byte, 11))
+	var be *BatchError
+	if !errors.As(err, &be) || be.Ops != 4 || !errors.Is(err, ErrValueTooLarge) {
+		t.Fatal(err)
+	}
+
+	if err := w.Put([]byte("y"), []byte("v")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(batches) != "[4 4 4 1]" {
+		t.Fatal(batches)
+	}
+
+	var keys string
+	err = ForEach(bdb, path, func(seq uint64, key, data []byte) error {
+		keys += string(key)
+		return nil
+	})
+	if err != nil || keys != "01234567y" {
+		t.Fatal(keys, err)
+	}
+}